@@ -1,223 +1,130 @@
 // Code generated by configgen. DO NOT EDIT.
 package config
 
-import (
-	"fmt"
-	"strings"
-	"github.com/knadh/koanf/v2"
-	"github.com/knadh/koanf/parsers/yaml"
-	"github.com/knadh/koanf/providers/env"
-	"github.com/knadh/koanf/providers/file"
-)
+import _ "embed"
 
 
 type Config struct {
 	Game GameStruct `koanf:"game"`
 	Web WebStruct `koanf:"web"`
 	Database DatabaseStruct `koanf:"database"`
-	Notifications NotificationsStruct `koanf:"notifications"`
-	Security SecurityStruct `koanf:"security"`
-	Cache CacheStruct `koanf:"cache"`
 	Auth AuthStruct `koanf:"auth"`
 	Features FeaturesStruct `koanf:"features"`
 	Monitoring MonitoringStruct `koanf:"monitoring"`
+	Notifications NotificationsStruct `koanf:"notifications"`
+	Cache CacheStruct `koanf:"cache"`
+	Security SecurityStruct `koanf:"security"`
 }
 
 
 type GameStruct struct {
-	World GameWorldStruct `koanf:"world"`
-	Player GamePlayerStruct `koanf:"player"`
-	Name string `koanf:"name" env:"DB_NAME"`
+	Name string `koanf:"name"`
 	Version string `koanf:"version"`
 	MaxPlayers int `koanf:"max_players"`
 	Difficulty string `koanf:"difficulty"`
-	PvpEnabled bool `koanf:"pvp_enabled"`
+	PVPEnabled bool `koanf:"pvp_enabled"`
+	World GameWorldStruct `koanf:"world"`
+	Player GamePlayerStruct `koanf:"player"`
 }
 
 
 type GameWorldStruct struct {
-	Size string `koanf:"size" env:"WORLD_SEED"`
-	WeatherEnabled bool `koanf:"weather_enabled" env:"WORLD_SEED"`
-	DayNightCycle bool `koanf:"day_night_cycle" env:"WORLD_SEED"`
-	SpawnPoint GameWorldSpawnPointStruct `koanf:"spawn_point"`
-	Name string `koanf:"name" env:"WORLD_SEED"`
+	Name string `koanf:"name"`
 	Seed string `koanf:"seed" env:"WORLD_SEED"`
+	Size string `koanf:"size"`
+	WeatherEnabled bool `koanf:"weather_enabled"`
+	DayNightCycle bool `koanf:"day_night_cycle"`
+	SpawnPoint GameWorldSpawnPointStruct `koanf:"spawn_point"`
 }
 
 
 type GameWorldSpawnPointStruct struct {
-	X int `koanf:"x" env:"WORLD_SEED"`
-	Y int `koanf:"y" env:"WORLD_SEED"`
-	Z int `koanf:"z" env:"WORLD_SEED"`
+	X int `koanf:"x"`
+	Y int `koanf:"y"`
+	Z int `koanf:"z"`
 }
 
 
 type GamePlayerStruct struct {
-	StarterKit []string `koanf:"starter_kit"`
 	StartingHealth int `koanf:"starting_health"`
-	StartingMoney string `koanf:"starting_money"`
+	StartingMoney string `koanf:"starting_money" env:"STARTING_MONEY"`
 	MaxInventorySlots int `koanf:"max_inventory_slots"`
 	RespawnTime int `koanf:"respawn_time"`
+	StarterKit []string `koanf:"starter_kit"`
 }
 
 
 type WebStruct struct {
+	Host string `koanf:"host" env:"SERVER_HOST"`
 	Port string `koanf:"port" env:"SERVER_PORT"`
-	SslEnabled string `koanf:"ssl_enabled"`
+	SSLEnabled string `koanf:"ssl_enabled" env:"SSL_ENABLED"`
 	AdminPanel bool `koanf:"admin_panel"`
-	Api WebApiStruct `koanf:"api"`
-	Host string `koanf:"host" env:"SERVER_HOST"`
+	API WebAPIStruct `koanf:"api"`
 }
 
 
-type WebApiStruct struct {
-	CorsEnabled bool `koanf:"cors_enabled"`
-	AllowedOrigins []string `koanf:"allowed_origins"`
+type WebAPIStruct struct {
 	RateLimit int `koanf:"rate_limit"`
 	Timeout string `koanf:"timeout"`
+	CORSEnabled bool `koanf:"cors_enabled"`
+	AllowedOrigins []string `koanf:"allowed_origins"`
 }
 
 
 type DatabaseStruct struct {
-	Migrations DatabaseMigrationsStruct `koanf:"migrations"`
 	Type string `koanf:"type"`
-	Connection string `koanf:"connection"`
+	Connection Secret `koanf:"connection" env:"DB_USER,DB_PASSWORD,DB_HOST,DB_PORT,DB_NAME,DB_SSL"`
 	Pool DatabasePoolStruct `koanf:"pool"`
-}
-
-
-type DatabaseMigrationsStruct struct {
-	BackupBeforeMigrate bool `koanf:"backup_before_migrate"`
-	Enabled bool `koanf:"enabled" env:"SSL_ENABLED"`
-	AutoMigrate string `koanf:"auto_migrate"`
+	Migrations DatabaseMigrationsStruct `koanf:"migrations"`
 }
 
 
 type DatabasePoolStruct struct {
-	MaxLifetime string `koanf:"max_lifetime"`
 	MaxConnections int `koanf:"max_connections"`
 	MinConnections int `koanf:"min_connections"`
 	IdleTimeout string `koanf:"idle_timeout"`
+	MaxLifetime string `koanf:"max_lifetime"`
 }
 
 
-type NotificationsStruct struct {
-	Webhooks NotificationsWebhooksStruct `koanf:"webhooks"`
-	Email NotificationsEmailStruct `koanf:"email"`
-}
-
-
-type NotificationsWebhooksStruct struct {
-	Discord NotificationsWebhooksDiscordStruct `koanf:"discord"`
-}
-
-
-type NotificationsWebhooksDiscordStruct struct {
-	Enabled string `koanf:"enabled" env:"SSL_ENABLED"`
-	Url string `koanf:"url" env:"DISCORD_CLIENT_ID"`
-	Events []string `koanf:"events" env:"DISCORD_CLIENT_ID"`
-}
-
-
-type NotificationsEmailStruct struct {
-	Password string `koanf:"password" env:"DB_PASSWORD"`
-	From string `koanf:"from" env:"EMAIL_ENABLED"`
-	Enabled string `koanf:"enabled" env:"SSL_ENABLED"`
-	SmtpHost string `koanf:"smtp_host" env:"EMAIL_ENABLED"`
-	SmtpPort string `koanf:"smtp_port" env:"EMAIL_ENABLED"`
-	Username string `koanf:"username" env:"EMAIL_ENABLED"`
-}
-
-
-type SecurityStruct struct {
-	RateLimiting SecurityRateLimitingStruct `koanf:"rate_limiting"`
-	Anticheat SecurityAnticheatStruct `koanf:"anticheat"`
-}
-
-
-type SecurityRateLimitingStruct struct {
-	Enabled bool `koanf:"enabled" env:"SSL_ENABLED"`
-	RequestsPerMinute int `koanf:"requests_per_minute"`
-	BurstSize int `koanf:"burst_size"`
-}
-
-
-type SecurityAnticheatStruct struct {
-	Enabled bool `koanf:"enabled" env:"SSL_ENABLED"`
-	StrictMode string `koanf:"strict_mode" env:"ANTICHEAT_STRICT"`
-	AutoBan bool `koanf:"auto_ban" env:"ANTICHEAT_STRICT"`
-	Checks SecurityAnticheatChecksStruct `koanf:"checks"`
-}
-
-
-type SecurityAnticheatChecksStruct struct {
-	ItemDuplication bool `koanf:"item_duplication" env:"ANTICHEAT_STRICT"`
-	SpeedHack bool `koanf:"speed_hack" env:"ANTICHEAT_STRICT"`
-	FlyHack bool `koanf:"fly_hack" env:"ANTICHEAT_STRICT"`
-}
-
-
-type CacheStruct struct {
-	Type string `koanf:"type"`
-	Redis CacheRedisStruct `koanf:"redis"`
-	Ttl CacheTtlStruct `koanf:"ttl"`
-}
-
-
-type CacheRedisStruct struct {
-	Database int `koanf:"database" env:"REDIS_HOST"`
-	Host string `koanf:"host" env:"SERVER_HOST"`
-	Port string `koanf:"port" env:"SERVER_PORT"`
-	Password string `koanf:"password" env:"DB_PASSWORD"`
-}
-
-
-type CacheTtlStruct struct {
-	WorldData string `koanf:"world_data"`
-	Leaderboards string `koanf:"leaderboards"`
-	ShopItems string `koanf:"shop_items"`
-	PlayerData string `koanf:"player_data"`
+type DatabaseMigrationsStruct struct {
+	Enabled bool `koanf:"enabled"`
+	AutoMigrate string `koanf:"auto_migrate" env:"AUTO_MIGRATE"`
+	BackupBeforeMigrate bool `koanf:"backup_before_migrate"`
 }
 
 
 type AuthStruct struct {
-	Jwt AuthJwtStruct `koanf:"jwt"`
-	Session AuthSessionStruct `koanf:"session"`
 	Providers AuthProvidersStruct `koanf:"providers"`
+	JWT AuthJWTStruct `koanf:"jwt"`
+	Session AuthSessionStruct `koanf:"session"`
 }
 
 
-type AuthJwtStruct struct {
-	Secret string `koanf:"secret" env:"GOOGLE_CLIENT_SECRET"`
-	ExpiresIn string `koanf:"expires_in" env:"JWT_SECRET"`
-	RefreshExpiresIn string `koanf:"refresh_expires_in" env:"JWT_SECRET"`
-}
-
-
-type AuthSessionStruct struct {
-	CookieName string `koanf:"cookie_name" env:"SESSION_COOKIE_NAME"`
-	Secure string `koanf:"secure" env:"SESSION_COOKIE_NAME"`
-	MaxAge int `koanf:"max_age" env:"SESSION_COOKIE_NAME"`
+type AuthProvidersStruct struct {
+	Google OAuthProvider `koanf:"google"`
+	Discord OAuthProvider `koanf:"discord"`
 }
 
 
-type AuthProvidersStruct struct {
-	Google AuthProvidersGoogleStruct `koanf:"google"`
-	Discord AuthProvidersDiscordStruct `koanf:"discord"`
+type OAuthProvider struct {
+	ClientID string `koanf:"client_id"`
+	ClientSecret Secret `koanf:"client_secret"`
+	Enabled bool `koanf:"enabled"`
 }
 
 
-type AuthProvidersGoogleStruct struct {
-	ClientId string `koanf:"client_id" env:"GOOGLE_CLIENT_ID"`
-	ClientSecret string `koanf:"client_secret" env:"GOOGLE_CLIENT_ID"`
-	Enabled bool `koanf:"enabled" env:"SSL_ENABLED"`
+type AuthJWTStruct struct {
+	Secret Secret `koanf:"secret" env:"JWT_SECRET"`
+	ExpiresIn string `koanf:"expires_in"`
+	RefreshExpiresIn string `koanf:"refresh_expires_in"`
 }
 
 
-type AuthProvidersDiscordStruct struct {
-	Enabled bool `koanf:"enabled" env:"SSL_ENABLED"`
-	ClientId string `koanf:"client_id" env:"DISCORD_CLIENT_ID"`
-	ClientSecret string `koanf:"client_secret" env:"DISCORD_CLIENT_ID"`
+type AuthSessionStruct struct {
+	CookieName string `koanf:"cookie_name" env:"SESSION_COOKIE_NAME"`
+	Secure string `koanf:"secure" env:"SESSION_SECURE"`
+	MaxAge int `koanf:"max_age"`
 }
 
 
@@ -229,44 +136,44 @@ type FeaturesStruct struct {
 
 
 type FeaturesChatStruct struct {
-	Channels []string `koanf:"channels"`
-	Enabled bool `koanf:"enabled" env:"SSL_ENABLED"`
+	Enabled bool `koanf:"enabled"`
 	MaxMessageLength int `koanf:"max_message_length"`
 	SpamProtection bool `koanf:"spam_protection"`
 	BadWordsFilter bool `koanf:"bad_words_filter"`
+	Channels []string `koanf:"channels"`
 }
 
 
 type FeaturesEconomyStruct struct {
+	InflationRate float64 `koanf:"inflation_rate"`
+	TaxRate string `koanf:"tax_rate" env:"TAX_RATE"`
 	DailyBonus int `koanf:"daily_bonus"`
 	Shop FeaturesEconomyShopStruct `koanf:"shop"`
-	InflationRate float64 `koanf:"inflation_rate"`
-	TaxRate string `koanf:"tax_rate"`
 }
 
 
 type FeaturesEconomyShopStruct struct {
-	SeasonalItems bool `koanf:"seasonal_items"`
 	RefreshInterval string `koanf:"refresh_interval"`
 	DiscountEvents bool `koanf:"discount_events"`
+	SeasonalItems bool `koanf:"seasonal_items"`
 }
 
 
 type FeaturesEventsStruct struct {
-	DoubleXp FeaturesEventsDoubleXpStruct `koanf:"double_xp"`
+	DoubleXP FeaturesEventsDoubleXPStruct `koanf:"double_xp"`
 	BossFights FeaturesEventsBossFightsStruct `koanf:"boss_fights"`
 }
 
 
-type FeaturesEventsDoubleXpStruct struct {
-	Enabled bool `koanf:"enabled" env:"SSL_ENABLED"`
+type FeaturesEventsDoubleXPStruct struct {
+	Enabled bool `koanf:"enabled"`
 	Schedule string `koanf:"schedule"`
 	Duration string `koanf:"duration"`
 }
 
 
 type FeaturesEventsBossFightsStruct struct {
-	Enabled bool `koanf:"enabled" env:"SSL_ENABLED"`
+	Enabled bool `koanf:"enabled"`
 	MinPlayers int `koanf:"min_players"`
 	RewardsMultiplier float64 `koanf:"rewards_multiplier"`
 }
@@ -279,54 +186,438 @@ type MonitoringStruct struct {
 
 
 type MonitoringMetricsStruct struct {
+	Enabled bool `koanf:"enabled"`
 	Endpoint string `koanf:"endpoint"`
 	CollectInterval string `koanf:"collect_interval"`
 	Collect MonitoringMetricsCollectStruct `koanf:"collect"`
-	Enabled bool `koanf:"enabled" env:"SSL_ENABLED"`
 }
 
 
 type MonitoringMetricsCollectStruct struct {
-	GameEvents bool `koanf:"game_events"`
 	PlayerCount bool `koanf:"player_count"`
 	ServerPerformance bool `koanf:"server_performance"`
+	GameEvents bool `koanf:"game_events"`
 }
 
 
 type MonitoringLoggingStruct struct {
-	Format string `koanf:"format" env:"FILE_LOGGING"`
-	Output string `koanf:"output" env:"FILE_LOGGING"`
-	File MonitoringLoggingFileStruct `koanf:"file"`
 	Level string `koanf:"level" env:"LOG_LEVEL"`
+	Format string `koanf:"format"`
+	Output string `koanf:"output"`
+	File MonitoringLoggingFileStruct `koanf:"file"`
 }
 
 
 type MonitoringLoggingFileStruct struct {
-	MaxAge string `koanf:"max_age" env:"FILE_LOGGING"`
-	Enabled string `koanf:"enabled" env:"SSL_ENABLED"`
-	Path string `koanf:"path" env:"FILE_LOGGING"`
-	MaxSize string `koanf:"max_size" env:"FILE_LOGGING"`
+	Enabled string `koanf:"enabled" env:"FILE_LOGGING"`
+	Path string `koanf:"path"`
+	MaxSize string `koanf:"max_size"`
+	MaxAge string `koanf:"max_age"`
 }
 
 
+type NotificationsStruct struct {
+	Email NotificationsEmailStruct `koanf:"email"`
+	Webhooks NotificationsWebhooksStruct `koanf:"webhooks"`
+}
 
-func NewConfig() (*Config, error) {
-	k := koanf.New(".")
-	
-	if err := k.Load(file.Provider("config/config.yaml"), yaml.Parser()); err != nil {
-		return nil, fmt.Errorf("error loading config file: %w", err)
-	}
-
-	if err := k.Load(env.Provider("", ".", func(s string) string {
-		return strings.Replace(strings.ToLower(s), "_", ".", -1)
-	}), nil); err != nil {
-		return nil, fmt.Errorf("error loading env vars: %w", err)
-	}
 
-	var cfg Config
-	if err := k.Unmarshal("", &cfg); err != nil {
-		return nil, fmt.Errorf("error unmarshaling config: %w", err)
-	}
+type NotificationsEmailStruct struct {
+	Enabled string `koanf:"enabled" env:"EMAIL_ENABLED"`
+	SMTPHost string `koanf:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort string `koanf:"smtp_port" env:"SMTP_PORT"`
+	Username string `koanf:"username" env:"SMTP_USER"`
+	Password Secret `koanf:"password" env:"SMTP_PASSWORD"`
+	From string `koanf:"from" env:"EMAIL_FROM"`
+}
+
+
+type NotificationsWebhooksStruct struct {
+	Discord NotificationsWebhooksDiscordStruct `koanf:"discord"`
+}
+
 
-	return &cfg, nil
+type NotificationsWebhooksDiscordStruct struct {
+	Enabled string `koanf:"enabled" env:"DISCORD_WEBHOOK_ENABLED"`
+	URL Secret `koanf:"url" env:"DISCORD_WEBHOOK_URL"`
+	Events []string `koanf:"events"`
+}
+
+
+type CacheStruct struct {
+	Type string `koanf:"type"`
+	Redis CacheRedisStruct `koanf:"redis"`
+	TTL CacheTTLStruct `koanf:"ttl"`
+}
+
+
+type CacheRedisStruct struct {
+	Host string `koanf:"host" env:"REDIS_HOST"`
+	Port string `koanf:"port" env:"REDIS_PORT"`
+	Password Secret `koanf:"password" env:"REDIS_PASSWORD"`
+	Database int `koanf:"database"`
+}
+
+
+type CacheTTLStruct struct {
+	PlayerData string `koanf:"player_data"`
+	WorldData string `koanf:"world_data"`
+	Leaderboards string `koanf:"leaderboards"`
+	ShopItems string `koanf:"shop_items"`
+}
+
+
+type SecurityStruct struct {
+	RateLimiting SecurityRateLimitingStruct `koanf:"rate_limiting"`
+	Anticheat SecurityAnticheatStruct `koanf:"anticheat"`
+}
+
+
+type SecurityRateLimitingStruct struct {
+	Enabled bool `koanf:"enabled"`
+	RequestsPerMinute int `koanf:"requests_per_minute"`
+	BurstSize int `koanf:"burst_size"`
+}
+
+
+type SecurityAnticheatStruct struct {
+	Enabled bool `koanf:"enabled"`
+	StrictMode string `koanf:"strict_mode" env:"ANTICHEAT_STRICT"`
+	AutoBan bool `koanf:"auto_ban"`
+	Checks SecurityAnticheatChecksStruct `koanf:"checks"`
+}
+
+
+type SecurityAnticheatChecksStruct struct {
+	SpeedHack bool `koanf:"speed_hack"`
+	FlyHack bool `koanf:"fly_hack"`
+	ItemDuplication bool `koanf:"item_duplication"`
+}
+
+
+var generatedFields = []FieldInfo{
+	{Path: "game.name", Secret: false, Reload: Reloadable},
+	{Path: "game.version", Secret: false, Reload: Reloadable},
+	{Path: "game.max_players", Secret: false, Reload: Reloadable},
+	{Path: "game.difficulty", Secret: false, Reload: Reloadable},
+	{Path: "game.pvp_enabled", Secret: false, Reload: Reloadable},
+	{Path: "game.world.name", Secret: false, Reload: Reloadable},
+	{Path: "game.world.seed", Secret: false, Reload: Reloadable, Env: []string{"WORLD_SEED"}},
+	{Path: "game.world.size", Secret: false, Reload: Reloadable},
+	{Path: "game.world.weather_enabled", Secret: false, Reload: Reloadable},
+	{Path: "game.world.day_night_cycle", Secret: false, Reload: Reloadable},
+	{Path: "game.world.spawn_point.x", Secret: false, Reload: Reloadable},
+	{Path: "game.world.spawn_point.y", Secret: false, Reload: Reloadable},
+	{Path: "game.world.spawn_point.z", Secret: false, Reload: Reloadable},
+	{Path: "game.player.starting_health", Secret: false, Reload: Reloadable},
+	{Path: "game.player.starting_money", Secret: false, Reload: Reloadable, Env: []string{"STARTING_MONEY"}},
+	{Path: "game.player.max_inventory_slots", Secret: false, Reload: Reloadable},
+	{Path: "game.player.respawn_time", Secret: false, Reload: Reloadable},
+	{Path: "game.player.starter_kit", Secret: false, Reload: Reloadable},
+	{Path: "web.host", Secret: false, Reload: RestartRequired, Env: []string{"SERVER_HOST"}},
+	{Path: "web.port", Secret: false, Reload: RestartRequired, Env: []string{"SERVER_PORT"}},
+	{Path: "web.ssl_enabled", Secret: false, Reload: Reloadable, Env: []string{"SSL_ENABLED"}},
+	{Path: "web.admin_panel", Secret: false, Reload: Reloadable},
+	{Path: "web.api.rate_limit", Secret: false, Reload: Reloadable},
+	{Path: "web.api.timeout", Secret: false, Reload: Reloadable},
+	{Path: "web.api.cors_enabled", Secret: false, Reload: Reloadable},
+	{Path: "web.api.allowed_origins", Secret: false, Reload: Reloadable},
+	{Path: "database.type", Secret: false, Reload: Reloadable},
+	{Path: "database.connection", Secret: true, Reload: RestartRequired, Env: []string{"DB_USER", "DB_PASSWORD", "DB_HOST", "DB_PORT", "DB_NAME", "DB_SSL"}},
+	{Path: "database.pool.max_connections", Secret: false, Reload: RestartRequired},
+	{Path: "database.pool.min_connections", Secret: false, Reload: RestartRequired},
+	{Path: "database.pool.idle_timeout", Secret: false, Reload: RestartRequired},
+	{Path: "database.pool.max_lifetime", Secret: false, Reload: RestartRequired},
+	{Path: "database.migrations.enabled", Secret: false, Reload: Reloadable},
+	{Path: "database.migrations.auto_migrate", Secret: false, Reload: Reloadable, Env: []string{"AUTO_MIGRATE"}},
+	{Path: "database.migrations.backup_before_migrate", Secret: false, Reload: Reloadable},
+	{Path: "auth.providers.google.client_id", Secret: false, Reload: Reloadable, Env: []string{"GOOGLE_CLIENT_ID"}},
+	{Path: "auth.providers.google.client_secret", Secret: true, Reload: Reloadable, Env: []string{"GOOGLE_CLIENT_SECRET"}},
+	{Path: "auth.providers.google.enabled", Secret: false, Reload: Reloadable},
+	{Path: "auth.providers.discord.client_id", Secret: false, Reload: Reloadable, Env: []string{"DISCORD_CLIENT_ID"}},
+	{Path: "auth.providers.discord.client_secret", Secret: true, Reload: Reloadable, Env: []string{"DISCORD_CLIENT_SECRET"}},
+	{Path: "auth.providers.discord.enabled", Secret: false, Reload: Reloadable},
+	{Path: "auth.jwt.secret", Secret: true, Reload: Reloadable, Env: []string{"JWT_SECRET"}},
+	{Path: "auth.jwt.expires_in", Secret: false, Reload: Reloadable},
+	{Path: "auth.jwt.refresh_expires_in", Secret: false, Reload: Reloadable},
+	{Path: "auth.session.cookie_name", Secret: false, Reload: Reloadable, Env: []string{"SESSION_COOKIE_NAME"}},
+	{Path: "auth.session.secure", Secret: false, Reload: Reloadable, Env: []string{"SESSION_SECURE"}},
+	{Path: "auth.session.max_age", Secret: false, Reload: Reloadable},
+	{Path: "features.chat.enabled", Secret: false, Reload: Reloadable},
+	{Path: "features.chat.max_message_length", Secret: false, Reload: Reloadable},
+	{Path: "features.chat.spam_protection", Secret: false, Reload: Reloadable},
+	{Path: "features.chat.bad_words_filter", Secret: false, Reload: Reloadable},
+	{Path: "features.chat.channels", Secret: false, Reload: Reloadable},
+	{Path: "features.economy.inflation_rate", Secret: false, Reload: Reloadable},
+	{Path: "features.economy.tax_rate", Secret: false, Reload: Reloadable, Env: []string{"TAX_RATE"}},
+	{Path: "features.economy.daily_bonus", Secret: false, Reload: Reloadable},
+	{Path: "features.economy.shop.refresh_interval", Secret: false, Reload: Reloadable},
+	{Path: "features.economy.shop.discount_events", Secret: false, Reload: Reloadable},
+	{Path: "features.economy.shop.seasonal_items", Secret: false, Reload: Reloadable},
+	{Path: "features.events.double_xp.enabled", Secret: false, Reload: Reloadable},
+	{Path: "features.events.double_xp.schedule", Secret: false, Reload: Reloadable},
+	{Path: "features.events.double_xp.duration", Secret: false, Reload: Reloadable},
+	{Path: "features.events.boss_fights.enabled", Secret: false, Reload: Reloadable},
+	{Path: "features.events.boss_fights.min_players", Secret: false, Reload: Reloadable},
+	{Path: "features.events.boss_fights.rewards_multiplier", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.metrics.enabled", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.metrics.endpoint", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.metrics.collect_interval", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.metrics.collect.player_count", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.metrics.collect.server_performance", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.metrics.collect.game_events", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.logging.level", Secret: false, Reload: Reloadable, Env: []string{"LOG_LEVEL"}},
+	{Path: "monitoring.logging.format", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.logging.output", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.logging.file.enabled", Secret: false, Reload: Reloadable, Env: []string{"FILE_LOGGING"}},
+	{Path: "monitoring.logging.file.path", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.logging.file.max_size", Secret: false, Reload: Reloadable},
+	{Path: "monitoring.logging.file.max_age", Secret: false, Reload: Reloadable},
+	{Path: "notifications.email.enabled", Secret: false, Reload: Reloadable, Env: []string{"EMAIL_ENABLED"}},
+	{Path: "notifications.email.smtp_host", Secret: false, Reload: Reloadable, Env: []string{"SMTP_HOST"}},
+	{Path: "notifications.email.smtp_port", Secret: false, Reload: Reloadable, Env: []string{"SMTP_PORT"}},
+	{Path: "notifications.email.username", Secret: false, Reload: Reloadable, Env: []string{"SMTP_USER"}},
+	{Path: "notifications.email.password", Secret: true, Reload: Reloadable, Env: []string{"SMTP_PASSWORD"}},
+	{Path: "notifications.email.from", Secret: false, Reload: Reloadable, Env: []string{"EMAIL_FROM"}},
+	{Path: "notifications.webhooks.discord.enabled", Secret: false, Reload: Reloadable, Env: []string{"DISCORD_WEBHOOK_ENABLED"}},
+	{Path: "notifications.webhooks.discord.url", Secret: true, Reload: Reloadable, Env: []string{"DISCORD_WEBHOOK_URL"}},
+	{Path: "notifications.webhooks.discord.events", Secret: false, Reload: Reloadable},
+	{Path: "cache.type", Secret: false, Reload: Reloadable},
+	{Path: "cache.redis.host", Secret: false, Reload: RestartRequired, Env: []string{"REDIS_HOST"}},
+	{Path: "cache.redis.port", Secret: false, Reload: RestartRequired, Env: []string{"REDIS_PORT"}},
+	{Path: "cache.redis.password", Secret: true, Reload: RestartRequired, Env: []string{"REDIS_PASSWORD"}},
+	{Path: "cache.redis.database", Secret: false, Reload: RestartRequired},
+	{Path: "cache.ttl.player_data", Secret: false, Reload: Reloadable},
+	{Path: "cache.ttl.world_data", Secret: false, Reload: Reloadable},
+	{Path: "cache.ttl.leaderboards", Secret: false, Reload: Reloadable},
+	{Path: "cache.ttl.shop_items", Secret: false, Reload: Reloadable},
+	{Path: "security.rate_limiting.enabled", Secret: false, Reload: Reloadable},
+	{Path: "security.rate_limiting.requests_per_minute", Secret: false, Reload: Reloadable},
+	{Path: "security.rate_limiting.burst_size", Secret: false, Reload: Reloadable},
+	{Path: "security.anticheat.enabled", Secret: false, Reload: Reloadable},
+	{Path: "security.anticheat.strict_mode", Secret: false, Reload: Reloadable, Env: []string{"ANTICHEAT_STRICT"}},
+	{Path: "security.anticheat.auto_ban", Secret: false, Reload: Reloadable},
+	{Path: "security.anticheat.checks.speed_hack", Secret: false, Reload: Reloadable},
+	{Path: "security.anticheat.checks.fly_hack", Secret: false, Reload: Reloadable},
+	{Path: "security.anticheat.checks.item_duplication", Secret: false, Reload: Reloadable},
+}
+
+// Template is the configuration template this file was generated from.
+// LoadConfig falls back to it when there is no config file on disk.
+//
+//go:embed config.yaml.template
+var Template string
+
+// Defaults returns the configuration the template describes when no
+// environment variables are set.
+func Defaults() *Config {
+	var cfg Config
+	cfg.Game.Name = "Super Adventure World"
+	cfg.Game.Version = "1.2.3"
+	cfg.Game.MaxPlayers = 100
+	cfg.Game.Difficulty = "normal"
+	cfg.Game.PVPEnabled = true
+	cfg.Game.World.Name = "Emerald Valley"
+	cfg.Game.World.Seed = "12345"
+	cfg.Game.World.Size = "large"
+	cfg.Game.World.WeatherEnabled = true
+	cfg.Game.World.DayNightCycle = true
+	cfg.Game.World.SpawnPoint.Y = 100
+	cfg.Game.Player.StartingHealth = 100
+	cfg.Game.Player.StartingMoney = "500"
+	cfg.Game.Player.MaxInventorySlots = 30
+	cfg.Game.Player.RespawnTime = 5
+	cfg.Game.Player.StarterKit = []string{"wooden_sword", "bread:5", "health_potion:2"}
+	cfg.Web.Host = "localhost"
+	cfg.Web.Port = "8080"
+	cfg.Web.SSLEnabled = "false"
+	cfg.Web.AdminPanel = true
+	cfg.Web.API.RateLimit = 1000
+	cfg.Web.API.Timeout = "30s"
+	cfg.Web.API.CORSEnabled = true
+	cfg.Web.API.AllowedOrigins = []string{"http://localhost:3000", "https://game.example.com"}
+	cfg.Database.Type = "postgresql"
+	cfg.Database.Pool.MaxConnections = 25
+	cfg.Database.Pool.MinConnections = 5
+	cfg.Database.Pool.IdleTimeout = "10m"
+	cfg.Database.Pool.MaxLifetime = "1h"
+	cfg.Database.Migrations.Enabled = true
+	cfg.Database.Migrations.AutoMigrate = "true"
+	cfg.Database.Migrations.BackupBeforeMigrate = true
+	cfg.Auth.Providers.Google.Enabled = true
+	cfg.Auth.Providers.Discord.Enabled = true
+	cfg.Auth.JWT.ExpiresIn = "24h"
+	cfg.Auth.JWT.RefreshExpiresIn = "7d"
+	cfg.Auth.Session.CookieName = "game_session"
+	cfg.Auth.Session.Secure = "false"
+	cfg.Auth.Session.MaxAge = 86400
+	cfg.Features.Chat.Enabled = true
+	cfg.Features.Chat.MaxMessageLength = 200
+	cfg.Features.Chat.SpamProtection = true
+	cfg.Features.Chat.BadWordsFilter = true
+	cfg.Features.Chat.Channels = []string{"global", "trade", "guild"}
+	cfg.Features.Economy.InflationRate = 0.02
+	cfg.Features.Economy.TaxRate = "0.05"
+	cfg.Features.Economy.DailyBonus = 100
+	cfg.Features.Economy.Shop.RefreshInterval = "6h"
+	cfg.Features.Economy.Shop.DiscountEvents = true
+	cfg.Features.Economy.Shop.SeasonalItems = true
+	cfg.Features.Events.DoubleXP.Enabled = true
+	cfg.Features.Events.DoubleXP.Schedule = "0 18 * * 6"
+	cfg.Features.Events.DoubleXP.Duration = "2h"
+	cfg.Features.Events.BossFights.Enabled = true
+	cfg.Features.Events.BossFights.MinPlayers = 5
+	cfg.Features.Events.BossFights.RewardsMultiplier = 2
+	cfg.Monitoring.Metrics.Enabled = true
+	cfg.Monitoring.Metrics.Endpoint = "/metrics"
+	cfg.Monitoring.Metrics.CollectInterval = "10s"
+	cfg.Monitoring.Metrics.Collect.PlayerCount = true
+	cfg.Monitoring.Metrics.Collect.ServerPerformance = true
+	cfg.Monitoring.Metrics.Collect.GameEvents = true
+	cfg.Monitoring.Logging.Level = "info"
+	cfg.Monitoring.Logging.Format = "json"
+	cfg.Monitoring.Logging.Output = "stdout"
+	cfg.Monitoring.Logging.File.Enabled = "false"
+	cfg.Monitoring.Logging.File.Path = "./logs"
+	cfg.Monitoring.Logging.File.MaxSize = "100MB"
+	cfg.Monitoring.Logging.File.MaxAge = "30d"
+	cfg.Notifications.Email.Enabled = "false"
+	cfg.Notifications.Email.SMTPPort = "587"
+	cfg.Notifications.Email.From = "noreply@game.com"
+	cfg.Notifications.Webhooks.Discord.Enabled = "false"
+	cfg.Notifications.Webhooks.Discord.Events = []string{"player_join", "player_leave", "server_start", "server_stop"}
+	cfg.Cache.Type = "redis"
+	cfg.Cache.Redis.Host = "localhost"
+	cfg.Cache.Redis.Port = "6379"
+	cfg.Cache.TTL.PlayerData = "15m"
+	cfg.Cache.TTL.WorldData = "5m"
+	cfg.Cache.TTL.Leaderboards = "1h"
+	cfg.Cache.TTL.ShopItems = "6h"
+	cfg.Security.RateLimiting.Enabled = true
+	cfg.Security.RateLimiting.RequestsPerMinute = 60
+	cfg.Security.RateLimiting.BurstSize = 10
+	cfg.Security.Anticheat.Enabled = true
+	cfg.Security.Anticheat.StrictMode = "false"
+	cfg.Security.Anticheat.AutoBan = true
+	cfg.Security.Anticheat.Checks.SpeedHack = true
+	cfg.Security.Anticheat.Checks.FlyHack = true
+	cfg.Security.Anticheat.Checks.ItemDuplication = true
+	return &cfg
+}
+
+// Diff lists the settings that differ between a and b, in template order.
+func Diff(a, b *Config) []Change {
+	var changes []Change
+	diffValue(&changes, "game.name", a.Game.Name, b.Game.Name)
+	diffValue(&changes, "game.version", a.Game.Version, b.Game.Version)
+	diffValue(&changes, "game.max_players", a.Game.MaxPlayers, b.Game.MaxPlayers)
+	diffValue(&changes, "game.difficulty", a.Game.Difficulty, b.Game.Difficulty)
+	diffValue(&changes, "game.pvp_enabled", a.Game.PVPEnabled, b.Game.PVPEnabled)
+	diffValue(&changes, "game.world.name", a.Game.World.Name, b.Game.World.Name)
+	diffValue(&changes, "game.world.seed", a.Game.World.Seed, b.Game.World.Seed)
+	diffValue(&changes, "game.world.size", a.Game.World.Size, b.Game.World.Size)
+	diffValue(&changes, "game.world.weather_enabled", a.Game.World.WeatherEnabled, b.Game.World.WeatherEnabled)
+	diffValue(&changes, "game.world.day_night_cycle", a.Game.World.DayNightCycle, b.Game.World.DayNightCycle)
+	diffValue(&changes, "game.world.spawn_point.x", a.Game.World.SpawnPoint.X, b.Game.World.SpawnPoint.X)
+	diffValue(&changes, "game.world.spawn_point.y", a.Game.World.SpawnPoint.Y, b.Game.World.SpawnPoint.Y)
+	diffValue(&changes, "game.world.spawn_point.z", a.Game.World.SpawnPoint.Z, b.Game.World.SpawnPoint.Z)
+	diffValue(&changes, "game.player.starting_health", a.Game.Player.StartingHealth, b.Game.Player.StartingHealth)
+	diffValue(&changes, "game.player.starting_money", a.Game.Player.StartingMoney, b.Game.Player.StartingMoney)
+	diffValue(&changes, "game.player.max_inventory_slots", a.Game.Player.MaxInventorySlots, b.Game.Player.MaxInventorySlots)
+	diffValue(&changes, "game.player.respawn_time", a.Game.Player.RespawnTime, b.Game.Player.RespawnTime)
+	diffSlice(&changes, "game.player.starter_kit", a.Game.Player.StarterKit, b.Game.Player.StarterKit)
+	diffValue(&changes, "web.host", a.Web.Host, b.Web.Host)
+	diffValue(&changes, "web.port", a.Web.Port, b.Web.Port)
+	diffValue(&changes, "web.ssl_enabled", a.Web.SSLEnabled, b.Web.SSLEnabled)
+	diffValue(&changes, "web.admin_panel", a.Web.AdminPanel, b.Web.AdminPanel)
+	diffValue(&changes, "web.api.rate_limit", a.Web.API.RateLimit, b.Web.API.RateLimit)
+	diffValue(&changes, "web.api.timeout", a.Web.API.Timeout, b.Web.API.Timeout)
+	diffValue(&changes, "web.api.cors_enabled", a.Web.API.CORSEnabled, b.Web.API.CORSEnabled)
+	diffSlice(&changes, "web.api.allowed_origins", a.Web.API.AllowedOrigins, b.Web.API.AllowedOrigins)
+	diffValue(&changes, "database.type", a.Database.Type, b.Database.Type)
+	diffValue(&changes, "database.connection", a.Database.Connection, b.Database.Connection)
+	diffValue(&changes, "database.pool.max_connections", a.Database.Pool.MaxConnections, b.Database.Pool.MaxConnections)
+	diffValue(&changes, "database.pool.min_connections", a.Database.Pool.MinConnections, b.Database.Pool.MinConnections)
+	diffValue(&changes, "database.pool.idle_timeout", a.Database.Pool.IdleTimeout, b.Database.Pool.IdleTimeout)
+	diffValue(&changes, "database.pool.max_lifetime", a.Database.Pool.MaxLifetime, b.Database.Pool.MaxLifetime)
+	diffValue(&changes, "database.migrations.enabled", a.Database.Migrations.Enabled, b.Database.Migrations.Enabled)
+	diffValue(&changes, "database.migrations.auto_migrate", a.Database.Migrations.AutoMigrate, b.Database.Migrations.AutoMigrate)
+	diffValue(&changes, "database.migrations.backup_before_migrate", a.Database.Migrations.BackupBeforeMigrate, b.Database.Migrations.BackupBeforeMigrate)
+	diffValue(&changes, "auth.providers.google.client_id", a.Auth.Providers.Google.ClientID, b.Auth.Providers.Google.ClientID)
+	diffValue(&changes, "auth.providers.google.client_secret", a.Auth.Providers.Google.ClientSecret, b.Auth.Providers.Google.ClientSecret)
+	diffValue(&changes, "auth.providers.google.enabled", a.Auth.Providers.Google.Enabled, b.Auth.Providers.Google.Enabled)
+	diffValue(&changes, "auth.providers.discord.client_id", a.Auth.Providers.Discord.ClientID, b.Auth.Providers.Discord.ClientID)
+	diffValue(&changes, "auth.providers.discord.client_secret", a.Auth.Providers.Discord.ClientSecret, b.Auth.Providers.Discord.ClientSecret)
+	diffValue(&changes, "auth.providers.discord.enabled", a.Auth.Providers.Discord.Enabled, b.Auth.Providers.Discord.Enabled)
+	diffValue(&changes, "auth.jwt.secret", a.Auth.JWT.Secret, b.Auth.JWT.Secret)
+	diffValue(&changes, "auth.jwt.expires_in", a.Auth.JWT.ExpiresIn, b.Auth.JWT.ExpiresIn)
+	diffValue(&changes, "auth.jwt.refresh_expires_in", a.Auth.JWT.RefreshExpiresIn, b.Auth.JWT.RefreshExpiresIn)
+	diffValue(&changes, "auth.session.cookie_name", a.Auth.Session.CookieName, b.Auth.Session.CookieName)
+	diffValue(&changes, "auth.session.secure", a.Auth.Session.Secure, b.Auth.Session.Secure)
+	diffValue(&changes, "auth.session.max_age", a.Auth.Session.MaxAge, b.Auth.Session.MaxAge)
+	diffValue(&changes, "features.chat.enabled", a.Features.Chat.Enabled, b.Features.Chat.Enabled)
+	diffValue(&changes, "features.chat.max_message_length", a.Features.Chat.MaxMessageLength, b.Features.Chat.MaxMessageLength)
+	diffValue(&changes, "features.chat.spam_protection", a.Features.Chat.SpamProtection, b.Features.Chat.SpamProtection)
+	diffValue(&changes, "features.chat.bad_words_filter", a.Features.Chat.BadWordsFilter, b.Features.Chat.BadWordsFilter)
+	diffSlice(&changes, "features.chat.channels", a.Features.Chat.Channels, b.Features.Chat.Channels)
+	diffValue(&changes, "features.economy.inflation_rate", a.Features.Economy.InflationRate, b.Features.Economy.InflationRate)
+	diffValue(&changes, "features.economy.tax_rate", a.Features.Economy.TaxRate, b.Features.Economy.TaxRate)
+	diffValue(&changes, "features.economy.daily_bonus", a.Features.Economy.DailyBonus, b.Features.Economy.DailyBonus)
+	diffValue(&changes, "features.economy.shop.refresh_interval", a.Features.Economy.Shop.RefreshInterval, b.Features.Economy.Shop.RefreshInterval)
+	diffValue(&changes, "features.economy.shop.discount_events", a.Features.Economy.Shop.DiscountEvents, b.Features.Economy.Shop.DiscountEvents)
+	diffValue(&changes, "features.economy.shop.seasonal_items", a.Features.Economy.Shop.SeasonalItems, b.Features.Economy.Shop.SeasonalItems)
+	diffValue(&changes, "features.events.double_xp.enabled", a.Features.Events.DoubleXP.Enabled, b.Features.Events.DoubleXP.Enabled)
+	diffValue(&changes, "features.events.double_xp.schedule", a.Features.Events.DoubleXP.Schedule, b.Features.Events.DoubleXP.Schedule)
+	diffValue(&changes, "features.events.double_xp.duration", a.Features.Events.DoubleXP.Duration, b.Features.Events.DoubleXP.Duration)
+	diffValue(&changes, "features.events.boss_fights.enabled", a.Features.Events.BossFights.Enabled, b.Features.Events.BossFights.Enabled)
+	diffValue(&changes, "features.events.boss_fights.min_players", a.Features.Events.BossFights.MinPlayers, b.Features.Events.BossFights.MinPlayers)
+	diffValue(&changes, "features.events.boss_fights.rewards_multiplier", a.Features.Events.BossFights.RewardsMultiplier, b.Features.Events.BossFights.RewardsMultiplier)
+	diffValue(&changes, "monitoring.metrics.enabled", a.Monitoring.Metrics.Enabled, b.Monitoring.Metrics.Enabled)
+	diffValue(&changes, "monitoring.metrics.endpoint", a.Monitoring.Metrics.Endpoint, b.Monitoring.Metrics.Endpoint)
+	diffValue(&changes, "monitoring.metrics.collect_interval", a.Monitoring.Metrics.CollectInterval, b.Monitoring.Metrics.CollectInterval)
+	diffValue(&changes, "monitoring.metrics.collect.player_count", a.Monitoring.Metrics.Collect.PlayerCount, b.Monitoring.Metrics.Collect.PlayerCount)
+	diffValue(&changes, "monitoring.metrics.collect.server_performance", a.Monitoring.Metrics.Collect.ServerPerformance, b.Monitoring.Metrics.Collect.ServerPerformance)
+	diffValue(&changes, "monitoring.metrics.collect.game_events", a.Monitoring.Metrics.Collect.GameEvents, b.Monitoring.Metrics.Collect.GameEvents)
+	diffValue(&changes, "monitoring.logging.level", a.Monitoring.Logging.Level, b.Monitoring.Logging.Level)
+	diffValue(&changes, "monitoring.logging.format", a.Monitoring.Logging.Format, b.Monitoring.Logging.Format)
+	diffValue(&changes, "monitoring.logging.output", a.Monitoring.Logging.Output, b.Monitoring.Logging.Output)
+	diffValue(&changes, "monitoring.logging.file.enabled", a.Monitoring.Logging.File.Enabled, b.Monitoring.Logging.File.Enabled)
+	diffValue(&changes, "monitoring.logging.file.path", a.Monitoring.Logging.File.Path, b.Monitoring.Logging.File.Path)
+	diffValue(&changes, "monitoring.logging.file.max_size", a.Monitoring.Logging.File.MaxSize, b.Monitoring.Logging.File.MaxSize)
+	diffValue(&changes, "monitoring.logging.file.max_age", a.Monitoring.Logging.File.MaxAge, b.Monitoring.Logging.File.MaxAge)
+	diffValue(&changes, "notifications.email.enabled", a.Notifications.Email.Enabled, b.Notifications.Email.Enabled)
+	diffValue(&changes, "notifications.email.smtp_host", a.Notifications.Email.SMTPHost, b.Notifications.Email.SMTPHost)
+	diffValue(&changes, "notifications.email.smtp_port", a.Notifications.Email.SMTPPort, b.Notifications.Email.SMTPPort)
+	diffValue(&changes, "notifications.email.username", a.Notifications.Email.Username, b.Notifications.Email.Username)
+	diffValue(&changes, "notifications.email.password", a.Notifications.Email.Password, b.Notifications.Email.Password)
+	diffValue(&changes, "notifications.email.from", a.Notifications.Email.From, b.Notifications.Email.From)
+	diffValue(&changes, "notifications.webhooks.discord.enabled", a.Notifications.Webhooks.Discord.Enabled, b.Notifications.Webhooks.Discord.Enabled)
+	diffValue(&changes, "notifications.webhooks.discord.url", a.Notifications.Webhooks.Discord.URL, b.Notifications.Webhooks.Discord.URL)
+	diffSlice(&changes, "notifications.webhooks.discord.events", a.Notifications.Webhooks.Discord.Events, b.Notifications.Webhooks.Discord.Events)
+	diffValue(&changes, "cache.type", a.Cache.Type, b.Cache.Type)
+	diffValue(&changes, "cache.redis.host", a.Cache.Redis.Host, b.Cache.Redis.Host)
+	diffValue(&changes, "cache.redis.port", a.Cache.Redis.Port, b.Cache.Redis.Port)
+	diffValue(&changes, "cache.redis.password", a.Cache.Redis.Password, b.Cache.Redis.Password)
+	diffValue(&changes, "cache.redis.database", a.Cache.Redis.Database, b.Cache.Redis.Database)
+	diffValue(&changes, "cache.ttl.player_data", a.Cache.TTL.PlayerData, b.Cache.TTL.PlayerData)
+	diffValue(&changes, "cache.ttl.world_data", a.Cache.TTL.WorldData, b.Cache.TTL.WorldData)
+	diffValue(&changes, "cache.ttl.leaderboards", a.Cache.TTL.Leaderboards, b.Cache.TTL.Leaderboards)
+	diffValue(&changes, "cache.ttl.shop_items", a.Cache.TTL.ShopItems, b.Cache.TTL.ShopItems)
+	diffValue(&changes, "security.rate_limiting.enabled", a.Security.RateLimiting.Enabled, b.Security.RateLimiting.Enabled)
+	diffValue(&changes, "security.rate_limiting.requests_per_minute", a.Security.RateLimiting.RequestsPerMinute, b.Security.RateLimiting.RequestsPerMinute)
+	diffValue(&changes, "security.rate_limiting.burst_size", a.Security.RateLimiting.BurstSize, b.Security.RateLimiting.BurstSize)
+	diffValue(&changes, "security.anticheat.enabled", a.Security.Anticheat.Enabled, b.Security.Anticheat.Enabled)
+	diffValue(&changes, "security.anticheat.strict_mode", a.Security.Anticheat.StrictMode, b.Security.Anticheat.StrictMode)
+	diffValue(&changes, "security.anticheat.auto_ban", a.Security.Anticheat.AutoBan, b.Security.Anticheat.AutoBan)
+	diffValue(&changes, "security.anticheat.checks.speed_hack", a.Security.Anticheat.Checks.SpeedHack, b.Security.Anticheat.Checks.SpeedHack)
+	diffValue(&changes, "security.anticheat.checks.fly_hack", a.Security.Anticheat.Checks.FlyHack, b.Security.Anticheat.Checks.FlyHack)
+	diffValue(&changes, "security.anticheat.checks.item_duplication", a.Security.Anticheat.Checks.ItemDuplication, b.Security.Anticheat.Checks.ItemDuplication)
+	return changes
+}
+
+// NewConfig loads the configuration like LoadConfig with default options.
+//
+// Deprecated: Use LoadConfig.
+func NewConfig() (*Config, error) {
+	return LoadConfig()
 }
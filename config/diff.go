@@ -0,0 +1,40 @@
+package config
+
+import "slices"
+
+// Change is a single setting that differs between two configurations.
+type Change struct {
+	// Path is the dotted YAML path of the setting, e.g. web.port.
+	Path string
+	// Old and New hold the field values. Secret settings hold Secret
+	// values, which print redacted.
+	Old interface{}
+	New interface{}
+	// Secret is set for settings annotated with @secret or named like one.
+	Secret bool
+	// RestartRequired is set for settings annotated with @restart.
+	RestartRequired bool
+}
+
+func diffValue[T comparable](changes *[]Change, path string, old, new T) {
+	if old != new {
+		*changes = append(*changes, newChange(path, old, new))
+	}
+}
+
+func diffSlice[T comparable](changes *[]Change, path string, old, new []T) {
+	if !slices.Equal(old, new) {
+		*changes = append(*changes, newChange(path, old, new))
+	}
+}
+
+func newChange(path string, old, new interface{}) Change {
+	info, _ := LookupField(path)
+	return Change{
+		Path:            path,
+		Old:             old,
+		New:             new,
+		Secret:          info.Secret,
+		RestartRequired: info.Reload == RestartRequired,
+	}
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"project/internal/environ"
+	"project/internal/placeholder"
+	"project/internal/sealed"
+	"project/internal/yamltree"
+)
+
+var floatPattern = regexp.MustCompile(`^[-+]?(\d+\.\d*|\.\d+|\d+)([eE][-+]?\d+)?$`)
+
+// expander substitutes placeholders and decrypts ENC[...] values in parsed
+// config files.
+type expander struct {
+	env     environ.Env
+	schemes placeholder.Schemes
+	// keyring returns the keys for encrypted values. It is only called
+	// once a file actually contains one.
+	keyring func() (*sealed.Keyring, error)
+}
+
+// expandTree substitutes placeholders in every scalar of a parsed config
+// file. Expansion happens after parsing, so a substituted value can never
+// change the structure of the document. Encrypted values are decrypted and
+// used verbatim, without placeholder expansion. Each scalar node is expanded
+// once, even when merge keys or aliases reach it through several paths, so
+// a substituted value is never parsed as a placeholder itself.
+//
+// It returns the unresolved required placeholders, skipping fields annotated
+// with @optional directly or through a parent section, and the source of
+// every leaf the file defines.
+func (x *expander) expandTree(filename string, root *yaml.Node) ([]requirement, map[string]Source, error) {
+	var requirements []requirement
+	sources := make(map[string]Source)
+	expanded := make(map[*yaml.Node]expansion)
+	var expandErr error
+
+	yamltree.Walk(root, func(e *yamltree.Entry) {
+		if e.Value.Kind == yaml.SequenceNode && e.Key != nil {
+			sources[e.Path] = Source{File: filename, Line: e.Value.Line}
+		}
+		if !e.IsLeaf() || expandErr != nil {
+			return
+		}
+
+		exp, ok := expanded[e.Value]
+		if !ok {
+			var err error
+			if exp, err = x.expandScalar(e); err != nil {
+				expandErr = fmt.Errorf("%w at %s:%d", err, filename, e.Value.Line)
+				return
+			}
+			expanded[e.Value] = exp
+		}
+		// Secrets are strings whatever their value looks like, so that
+		// "password: 12345" still decodes into a Secret.
+		if field, ok := LookupField(e.Path); ok && field.Secret {
+			e.Value.Tag = "!!str"
+		}
+		sources[e.Path] = Source{File: filename, Line: e.Value.Line, Vars: exp.vars, Encrypted: exp.encrypted}
+
+		if _, ok := e.Inherited("optional"); ok {
+			return
+		}
+		var conditions []string
+		for _, directive := range e.All("required-if") {
+			conditions = append(conditions, directive.Args)
+		}
+		for _, unset := range exp.unset {
+			requirements = append(requirements, requirement{
+				MissingVar: MissingVar{
+					Name:    unset.Name,
+					Path:    e.Path,
+					File:    filename,
+					Line:    e.Value.Line,
+					Message: unset.Message,
+				},
+				conditions: conditions,
+			})
+		}
+	})
+
+	return requirements, sources, expandErr
+}
+
+// expansion records what expanding one scalar node found.
+type expansion struct {
+	vars      []VarSource
+	unset     []*placeholder.UnsetError
+	encrypted bool
+}
+
+// expandScalar decrypts or expands the scalar at e in place.
+func (x *expander) expandScalar(e *yamltree.Entry) (expansion, error) {
+	if sealed.IsEncrypted(e.Value.Value) {
+		if err := x.decrypt(e); err != nil {
+			return expansion{}, fmt.Errorf("error decrypting %s: %w", e.Path, err)
+		}
+		return expansion{encrypted: true}, nil
+	}
+
+	t, err := placeholder.Parse(e.Value.Value)
+	if err != nil {
+		return expansion{}, fmt.Errorf("error parsing %s: %w", e.Path, err)
+	}
+	if len(t.Refs()) == 0 && !strings.Contains(e.Value.Value, "$$") {
+		return expansion{}, nil
+	}
+
+	var lookupErr error
+	lookup := func(name string) (string, bool) {
+		value, _, ok, err := x.env.Resolve(name)
+		if err != nil && lookupErr == nil {
+			lookupErr = err
+		}
+		return value, ok
+	}
+
+	exp := expansion{vars: x.varSources(t), unset: t.Unresolved(lookup)}
+
+	// Unset required variables are reported with their paths by
+	// checkRequired, so here they simply expand to empty strings.
+	value, err := t.Expand(lookup, x.schemes)
+	if err == nil || placeholder.OnlyUnset(err) {
+		err = lookupErr
+	}
+	if err != nil {
+		return expansion{}, fmt.Errorf("error expanding %s: %w", e.Path, err)
+	}
+
+	e.Value.Tag = scalarTag(e.Value, value)
+	e.Value.Value = value
+	return exp, nil
+}
+
+// varSources lists the variables referenced by t and where each was found.
+func (x *expander) varSources(t placeholder.Template) []VarSource {
+	var vars []VarSource
+	seen := make(map[string]bool)
+	for _, ref := range t.Vars() {
+		if seen[ref.Name] {
+			continue
+		}
+		seen[ref.Name] = true
+
+		var origin string
+		if _, o, ok, _ := x.env.Resolve(ref.Name); ok {
+			origin = o.String()
+		}
+		vars = append(vars, VarSource{Name: ref.Name, Origin: origin})
+	}
+	return vars
+}
+
+func (x *expander) decrypt(e *yamltree.Entry) error {
+	keyring, err := x.keyring()
+	if err != nil {
+		return err
+	}
+
+	value, typ, err := keyring.Decrypt(e.Path, e.Value.Value)
+	if err != nil {
+		return err
+	}
+
+	e.Value.Value = value
+	e.Value.Tag = "!!" + typ
+	return nil
+}
+
+// scalarTag types a substituted value the way YAML would have typed it had
+// it been written in place: quoted scalars stay strings, plain ones become
+// booleans or numbers when they look like one.
+func scalarTag(node *yaml.Node, value string) string {
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return "!!str"
+	}
+
+	switch value {
+	case "true", "True", "TRUE", "false", "False", "FALSE":
+		return "!!bool"
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return "!!int"
+	}
+	if floatPattern.MatchString(value) {
+		return "!!float"
+	}
+	return "!!str"
+}
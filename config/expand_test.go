@@ -0,0 +1,113 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+	"gopkg.in/yaml.v3"
+
+	"project/internal/environ"
+)
+
+func TestExpandTreeMergeKey(t *testing.T) {
+	const doc = `
+base: &base
+  url: "${X}"
+  literal: "${Y}"
+prod:
+  <<: *base
+`
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(doc), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	x := &expander{
+		env: environ.Env{Layers: environ.Stack{{Name: "test", Vars: map[string]string{
+			"X": "${cmd:touch /tmp/pwned}",
+			"Y": "a$$b",
+		}}}},
+		schemes: func(scheme, arg string) (string, error) {
+			t.Errorf("scheme %s:%s resolved from a substituted value", scheme, arg)
+			return "", nil
+		},
+	}
+
+	_, sources, err := x.expandTree("test.yaml", &root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Base map[string]string `yaml:"base"`
+		Prod map[string]string `yaml:"prod"`
+	}
+	if err := root.Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	for _, section := range []map[string]string{got.Base, got.Prod} {
+		if section["url"] != "${cmd:touch /tmp/pwned}" {
+			t.Errorf("url = %q, want the value of X verbatim", section["url"])
+		}
+		if section["literal"] != "a$$b" {
+			t.Errorf("literal = %q, want the value of Y verbatim", section["literal"])
+		}
+	}
+
+	if source := sources["base.url"]; len(source.Vars) != 1 || source.Vars[0].Name != "X" {
+		t.Errorf("sources[base.url] = %+v, want X", source)
+	}
+}
+
+func TestExpandTreeSecretScalars(t *testing.T) {
+	const doc = `
+auth:
+  jwt:
+    secret: 12345
+cache:
+  redis:
+    password: ${REDIS_PASSWORD}
+`
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(doc), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	x := &expander{env: environ.Env{Layers: environ.Stack{{Name: "test", Vars: map[string]string{
+		"REDIS_PASSWORD": "0700",
+	}}}}}
+	if _, _, err := x.expandTree("test.yaml", &root); err != nil {
+		t.Fatal(err)
+	}
+
+	mp := make(map[string]interface{})
+	if err := root.Decode(&mp); err != nil {
+		t.Fatal(err)
+	}
+	k := koanf.New(".")
+	if err := k.Load(mapProvider(mp), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Auth struct {
+			JWT struct {
+				Secret Secret `koanf:"secret"`
+			} `koanf:"jwt"`
+		} `koanf:"auth"`
+		Cache struct {
+			Redis struct {
+				Password Secret `koanf:"password"`
+			} `koanf:"redis"`
+		} `koanf:"cache"`
+	}
+	if err := k.Unmarshal("", &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Auth.JWT.Secret.Reveal() != "12345" {
+		t.Errorf("auth.jwt.secret = %q, want 12345", got.Auth.JWT.Secret.Reveal())
+	}
+	if got.Cache.Redis.Password.Reveal() != "0700" {
+		t.Errorf("cache.redis.password = %q, want 0700", got.Cache.Redis.Password.Reveal())
+	}
+}
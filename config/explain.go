@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Source says where the effective value of a setting came from.
+type Source struct {
+	// File and Line locate the YAML value that set the setting. They are
+	// empty when an environment variable such as WEB_PORT overrode the key
+	// directly.
+	File string
+	Line int
+	// Vars are the environment variables the value was expanded from, or
+	// the overriding variable.
+	Vars []VarSource
+	// Encrypted is set for ENC[...] values decrypted at load time.
+	Encrypted bool
+}
+
+// VarSource is an environment variable that contributed to a value.
+type VarSource struct {
+	Name string
+	// Origin is the layer or file the variable was found in, such as
+	// process or .env.local. It is empty when the variable was unset and
+	// the placeholder fell back to its default.
+	Origin string
+}
+
+func (s Source) String() string {
+	var parts []string
+	if s.File != "" {
+		parts = append(parts, fmt.Sprintf("%s:%d", s.File, s.Line))
+	}
+	if s.Encrypted {
+		parts = append(parts, "encrypted")
+	}
+	for _, v := range s.Vars {
+		if v.Origin == "" {
+			parts = append(parts, v.Name+" unset")
+		} else {
+			parts = append(parts, v.Name+" from "+v.Origin)
+		}
+	}
+	if len(parts) == 0 {
+		return "unset"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Setting is one leaf of the effective configuration.
+type Setting struct {
+	Path string
+	// Value is the effective value formatted for display. Secret values
+	// are redacted.
+	Value  string
+	Secret bool
+	Source Source
+	// Missing lists the required variables the setting references that
+	// are not set. Value is empty while any are missing.
+	Missing []MissingVar `json:",omitempty"`
+}
+
+// Explain loads the configuration the way LoadConfig does and returns every
+// leaf setting in template order, with its effective value and the source
+// that set it. Unlike LoadConfig it does not fail on missing required
+// variables, which it reports per setting, and it does not run validators.
+func Explain(opts ...Option) ([]Setting, error) {
+	k, sources, missing, err := newOptions(opts).merge()
+	if err != nil {
+		return nil, err
+	}
+
+	missingByPath := make(map[string][]MissingVar)
+	for _, v := range missing {
+		missingByPath[v.Path] = append(missingByPath[v.Path], v)
+	}
+
+	var settings []Setting
+	for _, f := range generatedFields {
+		value := ""
+		if v := k.Get(f.Path); v != nil && len(missingByPath[f.Path]) == 0 {
+			value = fmt.Sprint(v)
+		}
+		if f.Secret && value != "" {
+			value = NewSecret(value).String()
+		}
+
+		settings = append(settings, Setting{
+			Path:    f.Path,
+			Value:   value,
+			Secret:  f.Secret,
+			Source:  sources[f.Path],
+			Missing: missingByPath[f.Path],
+		})
+	}
+
+	return settings, nil
+}
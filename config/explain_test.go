@@ -0,0 +1,46 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestExplainMissingVars(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("DB_USER", "")
+	os.Unsetenv("DB_USER")
+	if err := os.Mkdir("config", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "game:\n  name: ${GAME_NAME:-quest}\ndatabase:\n  connection: postgresql://${DB_USER}@db\n"
+	if err := os.WriteFile("config/config.yaml", []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var missingErr *MissingEnvError
+	if _, err := LoadConfig(); !errors.As(err, &missingErr) {
+		t.Fatalf("LoadConfig error = %v, want a MissingEnvError", err)
+	}
+
+	settings, err := Explain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPath := make(map[string]Setting)
+	for _, s := range settings {
+		byPath[s.Path] = s
+	}
+
+	if s := byPath["game.name"]; s.Value != "quest" || len(s.Missing) != 0 {
+		t.Errorf("game.name = %q, missing %v; want quest", s.Value, s.Missing)
+	}
+
+	s := byPath["database.connection"]
+	if s.Value != "" || len(s.Missing) != 1 || s.Missing[0].Name != "DB_USER" {
+		t.Errorf("database.connection = %q, missing %v; want no value and DB_USER missing", s.Value, s.Missing)
+	}
+	if s.Source.File != "config/config.yaml" || s.Source.Line != 4 || len(s.Source.Vars) != 1 || s.Source.Vars[0].Origin != "" {
+		t.Errorf("database.connection source = %v, want config/config.yaml:4 with DB_USER unset", s.Source)
+	}
+}
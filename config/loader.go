@@ -3,93 +3,337 @@ package config
 import (
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
 
-	"github.com/knadh/koanf/parsers/yaml"
-	"github.com/knadh/koanf/providers/env"
-	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/knadh/koanf/maps"
 	"github.com/knadh/koanf/v2"
+	"gopkg.in/yaml.v3"
+
+	"project/internal/environ"
+	"project/internal/sealed"
 )
 
-func LoadConfig() (*Config, error) {
-	k := koanf.New(".")
+// Precedence decides which environment layer wins when a variable is defined
+// both in the process environment and in a dotenv file. Dotenv files are
+// always consulted as .env.local > .env.<profile>.local > .env.<profile> >
+// .env.
+type Precedence int
+
+const (
+	// ProcessFirst resolves variables as process env > dotenv files >
+	// template defaults. This is the default.
+	ProcessFirst Precedence = iota
+	// DotenvFirst resolves variables as dotenv files > process env >
+	// template defaults.
+	DotenvFirst
+)
+
+// Option configures LoadConfig.
+type Option func(*options)
+
+type options struct {
+	precedence    Precedence
+	profile       string
+	secretsDirs   []string
+	resolvers     map[string]Resolver
+	keyFile       string
+	validators    []func(*Config) error
+	onError       func(error)
+	restartPolicy RestartPolicy
+}
+
+// WithPrecedence sets the order in which environment layers are consulted.
+func WithPrecedence(p Precedence) Option {
+	return func(o *options) {
+		o.precedence = p
+	}
+}
+
+// WithProfile selects the environment profile, such as dev, staging or prod.
+// It defaults to the APP_ENV environment variable.
+func WithProfile(profile string) Option {
+	return func(o *options) {
+		o.profile = profile
+	}
+}
 
-	loadEnvFile(".env.local")
+// WithSecretsDir makes variables resolvable from files named after them in
+// dir, such as /run/secrets/DB_PASSWORD. It may be given more than once;
+// directories are searched in order, after NAME and NAME_FILE variables.
+func WithSecretsDir(dir string) Option {
+	return func(o *options) {
+		o.secretsDirs = append(o.secretsDirs, dir)
+	}
+}
+
+// WithKeyFile sets the AES key file or age identity file used to decrypt
+// ENC[...] values. It defaults to the CONFIG_KEY_FILE environment variable.
+func WithKeyFile(path string) Option {
+	return func(o *options) {
+		o.keyFile = path
+	}
+}
+
+// WithValidator adds a check that a loaded configuration must pass. During
+// Watch, a reload that fails validation is discarded.
+func WithValidator(validate func(*Config) error) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, validate)
+	}
+}
+
+func LoadConfig(opts ...Option) (*Config, error) {
+	return newOptions(opts).load()
+}
+
+// Environment returns the environment LoadConfig with the same options
+// resolves variables from: the process and dotenv layers in precedence
+// order, and the secrets directories.
+func Environment(opts ...Option) (environ.Env, error) {
+	return newOptions(opts).env()
+}
 
+func newOptions(opts []Option) options {
+	o := options{precedence: ProcessFirst, profile: os.Getenv("APP_ENV")}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// embeddedTemplate names the Template compiled into the package, which is
+// loaded when neither config.yaml nor the template exists on disk.
+const embeddedTemplate = "config.yaml.template (embedded)"
+
+// configFiles returns the config files to load, base file first.
+func (o options) configFiles() []string {
 	configFile := "config/config.yaml"
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
 		configFile = "config/config.yaml.template"
+		if _, err := os.Stat(configFile); os.IsNotExist(err) {
+			configFile = embeddedTemplate
+		}
+	}
+
+	files := []string{configFile}
+	if o.profile != "" {
+		overlay := fmt.Sprintf("config/config.%s.yaml", o.profile)
+		if _, err := os.Stat(overlay); err == nil {
+			files = append(files, overlay)
+		}
+	}
+	return files
+}
+
+func (o options) load() (*Config, error) {
+	cfg, _, _, err := o.loadTracked()
+	return cfg, err
+}
+
+// loadTracked runs the load pipeline and also returns the merged values and
+// the source of every setting, as applied layer by layer.
+func (o options) loadTracked() (*Config, *koanf.Koanf, map[string]Source, error) {
+	k, sources, missing, err := o.merge()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(missing) > 0 {
+		return nil, nil, nil, &MissingEnvError{Vars: missing}
 	}
 
-	content, err := os.ReadFile(configFile)
+	cfg, err := o.decode(k)
 	if err != nil {
-		return nil, fmt.Errorf("error reading config file: %w", err)
+		return nil, nil, nil, err
 	}
 
-	processedContent := expandEnvVars(string(content))
+	return cfg, k, sources, nil
+}
+
+// merge loads the config files and environment variables into one set of
+// values and records the source of every setting. Required variables that
+// are unset do not fail the merge; they are returned instead.
+func (o options) merge() (*koanf.Koanf, map[string]Source, []MissingVar, error) {
+	k := koanf.New(".")
 
-	if err := k.Load(rawbytes.Provider([]byte(processedContent)), yaml.Parser()); err != nil {
-		return nil, fmt.Errorf("error loading config file: %w", err)
+	env, err := o.env()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	layers := env.Layers
+	x := &expander{
+		env:     env,
+		schemes: schemesFor(buildResolvers(env, o.resolvers)),
+		keyring: keyringLoader(o.keyFile, layers),
 	}
 
-	if err := k.Load(env.Provider("", ".", func(s string) string {
-		return strings.ReplaceAll(strings.ToLower(s), "_", ".")
-	}), nil); err != nil {
-		return nil, fmt.Errorf("error loading env vars: %w", err)
+	var requirements []requirement
+	sources := make(map[string]Source)
+	for _, filename := range o.configFiles() {
+		fileRequirements, fileSources, err := loadConfigFile(k, filename, x)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		// A field redefined by an overlay no longer needs the variables
+		// referenced by the file it overrides.
+		kept := requirements[:0]
+		for _, r := range requirements {
+			if _, ok := fileSources[r.Path]; !ok {
+				kept = append(kept, r)
+			}
+		}
+		requirements = append(kept, fileRequirements...)
+
+		for path, source := range fileSources {
+			sources[path] = source
+		}
+	}
+
+	if err := k.Load(envProvider(layers.Vars()), nil); err != nil {
+		return nil, nil, nil, fmt.Errorf("error loading env vars: %w", err)
+	}
+	for path, source := range envSources(layers) {
+		_, inFile := sources[path]
+		if _, ok := LookupField(path); ok || inFile {
+			sources[path] = source
+		}
 	}
 
+	return k, sources, missingVars(k, requirements), nil
+}
+
+// decode unmarshals the merged values into a Config and validates it.
+func (o options) decode(k *koanf.Koanf) (*Config, error) {
 	var cfg Config
 	if err := k.Unmarshal("", &cfg); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	for _, validate := range o.validators {
+		if err := validate(&cfg); err != nil {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+
 	return &cfg, nil
 }
 
-func expandEnvVars(content string) string {
-	re := regexp.MustCompile(`\$\{([^}:]+)(?::([^}]*))?\}`)
-
-	return re.ReplaceAllStringFunc(content, func(match string) string {
-		submatches := re.FindStringSubmatch(match)
-		if len(submatches) < 2 {
-			return match
+func loadConfigFile(k *koanf.Koanf, filename string, x *expander) ([]requirement, map[string]Source, error) {
+	content := []byte(Template)
+	if filename != embeddedTemplate {
+		var err error
+		if content, err = os.ReadFile(filename); err != nil {
+			return nil, nil, fmt.Errorf("error reading config file: %w", err)
 		}
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, nil, fmt.Errorf("error parsing config file %s: %w", filename, err)
+	}
+
+	requirements, sources, err := x.expandTree(filename, &root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mp := make(map[string]interface{})
+	if err := root.Decode(&mp); err != nil {
+		return nil, nil, fmt.Errorf("error decoding config file %s: %w", filename, err)
+	}
+
+	if err := k.Load(mapProvider(mp), nil); err != nil {
+		return nil, nil, fmt.Errorf("error loading config file %s: %w", filename, err)
+	}
+
+	return requirements, sources, nil
+}
 
-		envVar := submatches[1]
-		defaultValue := ""
-		if len(submatches) > 2 {
-			defaultValue = submatches[2]
+// keyringLoader returns a function that reads the decryption keys on first
+// use, so configs without encrypted values need no key file.
+func keyringLoader(keyFile string, layers environ.Stack) func() (*sealed.Keyring, error) {
+	var keyring *sealed.Keyring
+	return func() (*sealed.Keyring, error) {
+		if keyring != nil {
+			return keyring, nil
 		}
 
-		if value := os.Getenv(envVar); value != "" {
-			return value
+		path := keyFile
+		if path == "" {
+			path, _ = layers.Lookup("CONFIG_KEY_FILE")
+		}
+		if path == "" {
+			return nil, fmt.Errorf("config contains encrypted values but no key file is set; use CONFIG_KEY_FILE or WithKeyFile")
 		}
 
-		return defaultValue
-	})
+		k, err := sealed.ReadKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keyring = k
+		return keyring, nil
+	}
 }
 
-func loadEnvFile(filename string) {
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		return
+func (o options) env() (environ.Env, error) {
+	layers, err := loadEnv(o.precedence, o.profile)
+	if err != nil {
+		return environ.Env{}, err
 	}
+	return environ.Env{Layers: layers, SecretsDirs: o.secretsDirs}, nil
+}
 
-	content, err := os.ReadFile(filename)
+func loadEnv(precedence Precedence, profile string) (environ.Stack, error) {
+	dotenv, err := environ.Dotenv(profile)
 	if err != nil {
-		return
-	}
-
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "#") {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-				value := strings.TrimSpace(parts[1])
-				os.Setenv(key, value)
-			}
+		return nil, err
+	}
+
+	if precedence == DotenvFirst {
+		return append(dotenv, environ.Process()), nil
+	}
+	return append(environ.Stack{environ.Process()}, dotenv...), nil
+}
+
+// mapProvider exposes an already decoded config file to koanf.
+type mapProvider map[string]interface{}
+
+func (p mapProvider) ReadBytes() ([]byte, error) {
+	return nil, fmt.Errorf("map provider does not support this method")
+}
+
+func (p mapProvider) Read() (map[string]interface{}, error) {
+	return p, nil
+}
+
+// envProvider exposes resolved environment variables to koanf, mapping
+// GAME_WORLD_NAME onto the game.world.name key.
+type envProvider map[string]string
+
+func (p envProvider) ReadBytes() ([]byte, error) {
+	return nil, fmt.Errorf("env provider does not support this method")
+}
+
+func (p envProvider) Read() (map[string]interface{}, error) {
+	mp := make(map[string]interface{}, len(p))
+	for key, value := range p {
+		mp[envKey(key)] = value
+	}
+	return maps.Unflatten(mp, "."), nil
+}
+
+func envKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", ".")
+}
+
+// envSources returns the source of each key envProvider sets, naming the
+// variable and the layer that supplied it.
+func envSources(layers environ.Stack) map[string]Source {
+	sources := make(map[string]Source)
+	for i := len(layers) - 1; i >= 0; i-- {
+		for name := range layers[i].Vars {
+			sources[envKey(name)] = Source{Vars: []VarSource{{Name: name, Origin: layers[i].Name}}}
 		}
 	}
+	return sources
 }
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"project/internal/sealed"
+)
+
+// writeEncryptedConfig writes a config whose JWT secret is an ENC[...] value
+// into a temporary working directory and points CONFIG_KEY_FILE at its key.
+func writeEncryptedConfig(t *testing.T, plaintext string) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	key, err := sealed.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := sealed.ParseKey([]byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := keyring.Encrypt("auth.jwt.secret", plaintext, "str")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile("config.key", []byte(key), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir("config", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "auth:\n  jwt:\n    secret: " + value + "\n"
+	if err := os.WriteFile("config/config.yaml", []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_KEY_FILE", "config.key")
+}
+
+func TestConstructorsDecrypt(t *testing.T) {
+	constructors := map[string]func() (*Config, error){
+		"LoadConfig": func() (*Config, error) { return LoadConfig() },
+		"NewConfig":  NewConfig,
+		"Watch": func() (*Config, error) {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			w, err := Watch(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			return w.Config(), nil
+		},
+	}
+
+	for name, load := range constructors {
+		t.Run(name, func(t *testing.T) {
+			writeEncryptedConfig(t, "hunter2")
+
+			cfg, err := load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cfg.Auth.JWT.Secret.Reveal(); got != "hunter2" {
+				t.Errorf("auth.jwt.secret = %q, want hunter2", got)
+			}
+		})
+	}
+
+	t.Run("Explain", func(t *testing.T) {
+		writeEncryptedConfig(t, "hunter2")
+
+		settings, err := Explain()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range settings {
+			if s.Path == "auth.jwt.secret" && (!s.Source.Encrypted || s.Value != redacted) {
+				t.Errorf("auth.jwt.secret = %q from %v, want a redacted decrypted value", s.Value, s.Source)
+			}
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package config
+
+import "sync"
+
+// ReloadClass says whether a setting can change while the server runs.
+type ReloadClass int
+
+const (
+	// Reloadable settings take effect on a hot reload.
+	Reloadable ReloadClass = iota
+	// RestartRequired settings, annotated with @restart in the template,
+	// only take effect after a restart.
+	RestartRequired
+)
+
+func (c ReloadClass) String() string {
+	if c == RestartRequired {
+		return "restart"
+	}
+	return "reload"
+}
+
+// FieldInfo is the metadata configgen records for a leaf setting.
+type FieldInfo struct {
+	// Path is the dotted YAML path of the setting.
+	Path   string
+	Secret bool
+	Reload ReloadClass
+	// Env lists the environment variables the setting's template value
+	// references. Sections sharing a @type declare no env tags, so this is
+	// where the variables of each section's fields are recorded.
+	Env []string
+}
+
+// Fields returns the metadata of every leaf setting in template order.
+func Fields() []FieldInfo {
+	return append([]FieldInfo(nil), generatedFields...)
+}
+
+var fieldsByPath = sync.OnceValue(func() map[string]FieldInfo {
+	m := make(map[string]FieldInfo, len(generatedFields))
+	for _, f := range generatedFields {
+		m[f.Path] = f
+	}
+	return m
+})
+
+// LookupField returns the metadata of the setting at path.
+func LookupField(path string) (FieldInfo, bool) {
+	f, ok := fieldsByPath()[path]
+	return f, ok
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// MissingEnvError lists every required placeholder that could not be
+// resolved while loading the configuration.
+type MissingEnvError struct {
+	Vars []MissingVar
+}
+
+// MissingVar is a required variable referenced by a config field.
+type MissingVar struct {
+	Name string
+	// Path is the YAML path of the field that references the variable.
+	Path string
+	File string
+	Line int
+	// Message is the text of a ${VAR:?message} placeholder, if any.
+	Message string
+}
+
+func (e *MissingEnvError) Error() string {
+	vars := make([]string, 0, len(e.Vars))
+	for _, v := range e.Vars {
+		if v.Message != "" {
+			vars = append(vars, fmt.Sprintf("%s (%s: %s)", v.Name, v.Path, v.Message))
+		} else {
+			vars = append(vars, fmt.Sprintf("%s (%s)", v.Name, v.Path))
+		}
+	}
+	return "missing required environment variables: " + strings.Join(vars, ", ")
+}
+
+// requirement is a missing variable together with the @required-if
+// conditions that must hold for it to matter.
+type requirement struct {
+	MissingVar
+	conditions []string
+}
+
+// missingVars returns the requirements whose @required-if conditions all
+// hold in the loaded configuration.
+func missingVars(k *koanf.Koanf, requirements []requirement) []MissingVar {
+	var missing []MissingVar
+	for _, r := range requirements {
+		applies := true
+		for _, condition := range r.conditions {
+			if !k.Bool(condition) {
+				applies = false
+				break
+			}
+		}
+		if applies {
+			missing = append(missing, r.MissingVar)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"project/internal/environ"
+	"project/internal/placeholder"
+)
+
+// Resolver resolves ${scheme:arg} placeholders for one scheme, such as
+// ${vault:secret/data/db#password}. The argument has nested placeholders
+// already expanded.
+type Resolver interface {
+	Resolve(arg string) (string, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface.
+type ResolverFunc func(arg string) (string, error)
+
+// Resolve calls f(arg).
+func (f ResolverFunc) Resolve(arg string) (string, error) {
+	return f(arg)
+}
+
+var (
+	resolversMu sync.RWMutex
+	resolvers   = map[string]Resolver{}
+)
+
+// RegisterResolver makes r available to every LoadConfig call under scheme.
+// Schemes are lowercase letters and digits, starting with a letter. The
+// built-in env, file, cmd and base64 schemes can be overridden.
+func RegisterResolver(scheme string, r Resolver) {
+	if !placeholder.IsScheme(scheme) {
+		panic(fmt.Sprintf("config: invalid resolver scheme %q", scheme))
+	}
+
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	resolvers[scheme] = r
+}
+
+// WithResolver registers r under scheme for a single LoadConfig call, taking
+// precedence over RegisterResolver. Tests can use it to substitute a fake
+// secret store.
+func WithResolver(scheme string, r Resolver) Option {
+	return func(o *options) {
+		if o.resolvers == nil {
+			o.resolvers = make(map[string]Resolver)
+		}
+		o.resolvers[scheme] = r
+	}
+}
+
+// buildResolvers combines the built-in resolvers, the registered ones and
+// those passed as options, in increasing order of precedence.
+func buildResolvers(env environ.Env, overrides map[string]Resolver) map[string]Resolver {
+	all := map[string]Resolver{
+		"env":    envResolver{env: env},
+		"file":   ResolverFunc(environ.ReadSecretFile),
+		"cmd":    ResolverFunc(resolveCmd),
+		"base64": ResolverFunc(resolveBase64),
+	}
+
+	resolversMu.RLock()
+	for scheme, r := range resolvers {
+		all[scheme] = r
+	}
+	resolversMu.RUnlock()
+
+	for scheme, r := range overrides {
+		all[scheme] = r
+	}
+
+	return all
+}
+
+func schemesFor(resolvers map[string]Resolver) placeholder.Schemes {
+	return func(scheme, arg string) (string, error) {
+		r, ok := resolvers[scheme]
+		if !ok {
+			return "", fmt.Errorf("unknown placeholder scheme %q; %s", scheme, placeholder.LegacyDefaultHint(scheme, arg))
+		}
+
+		value, err := r.Resolve(arg)
+		if err != nil {
+			return "", fmt.Errorf("%s resolver: %w", scheme, err)
+		}
+		return value, nil
+	}
+}
+
+// envResolver implements ${env:NAME}, looking the variable up in the same
+// layers as plain ${NAME} placeholders.
+type envResolver struct {
+	env environ.Env
+}
+
+func (r envResolver) Resolve(name string) (string, error) {
+	value, _, _, err := r.env.Resolve(name)
+	return value, err
+}
+
+// resolveCmd implements ${cmd:program args...}. The command is run without a
+// shell and its standard output, minus the trailing newline, is the value.
+func resolveCmd(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", fmt.Errorf("empty command")
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// resolveBase64 implements ${base64:...}, decoding standard base64.
+func resolveBase64(data string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
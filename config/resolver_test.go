@@ -0,0 +1,29 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"project/internal/environ"
+)
+
+func TestRegisterResolverInvalidScheme(t *testing.T) {
+	for _, scheme := range []string{"", "File", "1x", "my-scheme"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterResolver(%q) did not panic", scheme)
+				}
+			}()
+			RegisterResolver(scheme, ResolverFunc(func(string) (string, error) { return "", nil }))
+		}()
+	}
+}
+
+func TestUnknownSchemeHint(t *testing.T) {
+	schemes := schemesFor(buildResolvers(environ.Env{}, nil))
+	_, err := schemes("name", "anon")
+	if err == nil || !strings.Contains(err.Error(), "${name:-anon}") {
+		t.Errorf("error = %v, want a hint for ${name:-anon}", err)
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+const redacted = "[REDACTED]"
+
+// Secret holds a sensitive configuration value such as a password or an
+// OAuth client secret. It redacts itself whenever it is printed, logged or
+// serialised; the actual value is only available through Reveal.
+type Secret struct {
+	value string
+}
+
+// NewSecret wraps value in a Secret.
+func NewSecret(value string) Secret {
+	return Secret{value: value}
+}
+
+// Reveal returns the secret value.
+func (s Secret) Reveal() string {
+	return s.value
+}
+
+// IsSet reports whether the secret has a non-empty value.
+func (s Secret) IsSet() bool {
+	return s.value != ""
+}
+
+// String returns a redacted placeholder, or an empty string when the secret
+// is not set.
+func (s Secret) String() string {
+	if s.value == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString implements fmt.GoStringer for the %#v verb.
+func (s Secret) GoString() string {
+	return fmt.Sprintf("config.Secret(%q)", s.String())
+}
+
+// Format implements fmt.Formatter so that no verb, including %s, %x and %q,
+// can print the secret value.
+func (s Secret) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		io.WriteString(f, s.GoString())
+	case verb == 'q':
+		fmt.Fprintf(f, "%q", s.String())
+	default:
+		io.WriteString(f, s.String())
+	}
+}
+
+// LogValue implements slog.LogValuer.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, which koanf uses to
+// decode string values into Secret fields.
+func (s *Secret) UnmarshalText(text []byte) error {
+	s.value = string(text)
+	return nil
+}
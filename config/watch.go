@@ -0,0 +1,240 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"project/internal/environ"
+)
+
+// configDir holds the config files Watch watches besides the dotenv files in
+// the working directory.
+const configDir = "config"
+
+// reloadDelay coalesces the bursts of events editors produce when saving.
+const reloadDelay = 100 * time.Millisecond
+
+// Watcher holds the live configuration and reloads it when its files change.
+type Watcher struct {
+	current atomic.Pointer[Config]
+	opts    options
+	fn      func(old, new *Config, diff []Change)
+	// held maps the @restart settings KeepRunningRestart has held back to
+	// the new value last reported for each. Only the reload goroutine
+	// uses it.
+	held map[string]interface{}
+}
+
+// RestartPolicy decides what Watch does with a reload that changes settings
+// annotated with @restart.
+type RestartPolicy int
+
+const (
+	// KeepRunningRestart keeps such settings at their running values and
+	// applies the rest of the reload. Settings it holds back are reported
+	// to the reload error handler as a *RestartRequiredError, each once
+	// per new value. This is the default.
+	KeepRunningRestart RestartPolicy = iota
+	// FlagRestart applies the reload and leaves it to the callback to act
+	// on changes with RestartRequired set.
+	FlagRestart
+)
+
+// RestartRequiredError reports settings whose new values a reload did not
+// apply because they only take effect after a restart.
+type RestartRequiredError struct {
+	Paths []string
+}
+
+func (e *RestartRequiredError) Error() string {
+	return fmt.Sprintf("reload changes settings that require a restart: %s", strings.Join(e.Paths, ", "))
+}
+
+// WithRestartPolicy sets how Watch handles reloads touching @restart
+// settings.
+func WithRestartPolicy(p RestartPolicy) Option {
+	return func(o *options) {
+		o.restartPolicy = p
+	}
+}
+
+// WithReloadErrorHandler sets the function Watch calls when a reload fails
+// to load or validate. The previous configuration stays active either way.
+func WithReloadErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// Watch loads the configuration and then reloads it whenever config.yaml,
+// the profile overlay or one of the dotenv files changes, until ctx is done.
+// A reload runs the full load and validation pipeline; the new
+// configuration replaces the current one only if it succeeds, after which
+// fn is called with the changed settings.
+func Watch(ctx context.Context, fn func(old, new *Config, diff []Change), opts ...Option) (*Watcher, error) {
+	w := &Watcher{opts: newOptions(opts), fn: fn}
+
+	cfg, err := w.opts.load()
+	if err != nil {
+		return nil, err
+	}
+	w.current.Store(cfg)
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("error creating file watcher: %w", err)
+	}
+
+	// Watch directories rather than files so that editors replacing a
+	// file through a rename do not end the watch. A missing config
+	// directory, as when running from the embedded template, is watched
+	// once it is created.
+	for _, dir := range []string{".", configDir} {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+		if err := fsw.Add(dir); err != nil {
+			fsw.Close()
+			return nil, fmt.Errorf("error watching %s: %w", dir, err)
+		}
+	}
+
+	go w.run(ctx, fsw)
+
+	return w, nil
+}
+
+// Config returns the current configuration. It is safe for concurrent use;
+// callers should not modify the returned value.
+func (w *Watcher) Config() *Config {
+	return w.current.Load()
+}
+
+func (w *Watcher) run(ctx context.Context, fsw *fsnotify.Watcher) {
+	defer fsw.Close()
+
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == configDir && event.Has(fsnotify.Create) {
+				if err := fsw.Add(configDir); err != nil {
+					w.reportError(fmt.Errorf("error watching %s: %w", configDir, err))
+				}
+				// Files written before the watch was added sent
+				// no events of their own.
+				timer.Reset(reloadDelay)
+			}
+			if w.watches(event.Name) {
+				timer.Reset(reloadDelay)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+			w.reportError(fmt.Errorf("error watching config files: %w", err))
+		case <-timer.C:
+			w.reload()
+		}
+	}
+}
+
+// watches reports whether a change to name can affect the configuration.
+func (w *Watcher) watches(name string) bool {
+	name = filepath.Clean(name)
+
+	candidates := append([]string{"config/config.yaml", "config/config.yaml.template"}, environ.Files(w.opts.profile)...)
+	if w.opts.profile != "" {
+		candidates = append(candidates, fmt.Sprintf("config/config.%s.yaml", w.opts.profile))
+	}
+
+	for _, candidate := range candidates {
+		if name == filepath.Clean(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) reload() {
+	cfg, k, _, err := w.opts.loadTracked()
+	if err != nil {
+		w.reportError(err)
+		return
+	}
+
+	old := w.current.Load()
+	diff := Diff(old, cfg)
+
+	if w.opts.restartPolicy == KeepRunningRestart {
+		var paths []string
+		held := make(map[string]interface{})
+		for _, change := range diff {
+			if !change.RestartRequired {
+				continue
+			}
+			held[change.Path] = change.New
+			if last, ok := w.held[change.Path]; !ok || !reflect.DeepEqual(last, change.New) {
+				paths = append(paths, change.Path)
+			}
+			if err := k.Set(change.Path, loadedValue(change.Old)); err != nil {
+				w.reportError(fmt.Errorf("error keeping %s: %w", change.Path, err))
+				return
+			}
+		}
+
+		if len(held) > 0 {
+			// Decode again so validators see the values that will
+			// actually run.
+			if cfg, err = w.opts.decode(k); err != nil {
+				w.reportError(err)
+				return
+			}
+			diff = Diff(old, cfg)
+		}
+		w.held = held
+		if len(paths) > 0 {
+			w.reportError(&RestartRequiredError{Paths: paths})
+		}
+	}
+
+	if len(diff) == 0 {
+		return
+	}
+
+	w.current.Store(cfg)
+	if w.fn != nil {
+		w.fn(old, cfg, diff)
+	}
+}
+
+func (w *Watcher) reportError(err error) {
+	if w.opts.onError != nil {
+		w.opts.onError(err)
+	}
+}
+
+// loadedValue converts a field value back to the form the loader merges, so
+// that it decodes into the same value again.
+func loadedValue(v interface{}) interface{} {
+	if s, ok := v.(Secret); ok {
+		return s.Reveal()
+	}
+	return v
+}
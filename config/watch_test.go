@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// newTestWatcher returns a Watcher over a config.yaml in a temporary working
+// directory, loaded once, and the errors its reloads report.
+func newTestWatcher(t *testing.T, opts ...Option) (*Watcher, *[]error) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("config", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeWatchedConfig(t, "8080", "first")
+
+	var errs []error
+	opts = append(opts, WithReloadErrorHandler(func(err error) { errs = append(errs, err) }))
+	w := &Watcher{opts: newOptions(opts)}
+
+	cfg, err := w.opts.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.current.Store(cfg)
+	return w, &errs
+}
+
+func writeWatchedConfig(t *testing.T, port, name string) {
+	t.Helper()
+	content := "web:\n  port: " + port + "\ngame:\n  name: " + name + "\n"
+	if err := os.WriteFile("config/config.yaml", []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadKeepsRestartSettings(t *testing.T) {
+	w, errs := newTestWatcher(t)
+	var reloads int
+	w.fn = func(old, new *Config, diff []Change) { reloads++ }
+
+	// A restart-only change is held back while the reloadable one applies.
+	// It is reported once per new value on disk.
+	steps := []struct {
+		port, name string
+		reported   bool
+	}{
+		{"9090", "second", true},
+		{"9090", "third", false},
+		{"9091", "fourth", true},
+		{"8080", "fifth", false},
+		{"9091", "sixth", true},
+	}
+	for _, step := range steps {
+		*errs = nil
+		writeWatchedConfig(t, step.port, step.name)
+		w.reload()
+
+		if got := w.Config(); got.Web.Port != "8080" || got.Game.Name != step.name {
+			t.Errorf("after reload: web.port = %q, game.name = %q; want 8080, %q", got.Web.Port, got.Game.Name, step.name)
+		}
+
+		var restart *RestartRequiredError
+		switch {
+		case step.reported && (len(*errs) != 1 || !errors.As((*errs)[0], &restart) || len(restart.Paths) != 1 || restart.Paths[0] != "web.port"):
+			t.Errorf("port %s: reload errors = %v, want a RestartRequiredError for web.port", step.port, *errs)
+		case !step.reported && len(*errs) != 0:
+			t.Errorf("port %s: reload errors = %v, want none", step.port, *errs)
+		}
+	}
+	if reloads != len(steps) {
+		t.Errorf("callback ran %d times, want %d", reloads, len(steps))
+	}
+}
+
+func TestReloadFlagRestart(t *testing.T) {
+	w, errs := newTestWatcher(t, WithRestartPolicy(FlagRestart))
+	var diff []Change
+	w.fn = func(old, new *Config, d []Change) { diff = d }
+
+	writeWatchedConfig(t, "9090", "second")
+	w.reload()
+
+	if len(*errs) != 0 {
+		t.Errorf("reload errors = %v, want none", *errs)
+	}
+	if got := w.Config(); got.Web.Port != "9090" || got.Game.Name != "second" {
+		t.Errorf("after reload: web.port = %q, game.name = %q; want 9090, second", got.Web.Port, got.Game.Name)
+	}
+	flagged := make(map[string]bool)
+	for _, change := range diff {
+		flagged[change.Path] = change.RestartRequired
+	}
+	if len(flagged) != 2 || !flagged["web.port"] || flagged["game.name"] {
+		t.Errorf("diff = %+v, want web.port flagged and game.name not", diff)
+	}
+}
+
+func TestWatchWithoutConfigDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	for _, name := range []string{
+		"DB_USER", "DB_PASSWORD", "DB_HOST", "DB_PORT", "DB_NAME", "JWT_SECRET",
+		"GOOGLE_CLIENT_ID", "GOOGLE_CLIENT_SECRET", "DISCORD_CLIENT_ID", "DISCORD_CLIENT_SECRET",
+	} {
+		t.Setenv(name, "x")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloaded := make(chan *Config, 1)
+	w, err := Watch(ctx, func(old, new *Config, diff []Change) { reloaded <- new })
+	if err != nil {
+		t.Fatal(err)
+	}
+	initial := w.Config().Game.Name
+
+	// Creating the directory later starts watching it.
+	if err := os.Mkdir("config", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * reloadDelay)
+	writeWatchedConfig(t, "8080", "created")
+
+	select {
+	case cfg := <-reloaded:
+		if cfg.Game.Name != "created" {
+			t.Errorf("game.name = %q after reload, want created (was %q)", cfg.Game.Name, initial)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("config/config.yaml created after Watch was not reloaded")
+	}
+}
@@ -0,0 +1,196 @@
+// Package environ resolves environment variables across layered sources such
+// as the process environment and dotenv files, without touching the process
+// environment itself.
+package environ
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Layer is a named set of environment variables.
+type Layer struct {
+	Name string
+	Vars map[string]string
+}
+
+// Process returns the current process environment as a layer.
+func Process() Layer {
+	vars := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			vars[key] = value
+		}
+	}
+	return Layer{Name: "process", Vars: vars}
+}
+
+// ReadFile parses a dotenv file into a layer named after the file. A missing
+// file yields an empty layer.
+func ReadFile(filename string) (Layer, error) {
+	layer := Layer{Name: filename, Vars: make(map[string]string)}
+
+	content, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return layer, nil
+	}
+	if err != nil {
+		return layer, fmt.Errorf("error reading env file %s: %w", filename, err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if key, value, ok := ParseLine(line); ok {
+			layer.Vars[key] = value
+		}
+	}
+
+	return layer, nil
+}
+
+// ParseLine splits a KEY=value dotenv line. Blank lines and comments yield
+// ok == false.
+func ParseLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", "", false
+	}
+
+	return strings.TrimSpace(key), strings.TrimSpace(value), true
+}
+
+// Stack resolves variables across layers. Earlier layers take precedence
+// over later ones.
+type Stack []Layer
+
+// Lookup returns the value of the named variable from the first layer that
+// defines it.
+func (s Stack) Lookup(name string) (string, bool) {
+	for _, layer := range s {
+		if value, ok := layer.Vars[name]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Vars merges all layers into a single map, honouring layer precedence.
+func (s Stack) Vars() map[string]string {
+	vars := make(map[string]string)
+	for i := len(s) - 1; i >= 0; i-- {
+		for key, value := range s[i].Vars {
+			vars[key] = value
+		}
+	}
+	return vars
+}
+
+// Files returns the dotenv files consulted for profile, ordered from lowest
+// to highest precedence: .env, .env.<profile>, .env.<profile>.local and
+// .env.local.
+func Files(profile string) []string {
+	files := []string{".env"}
+	if profile != "" {
+		files = append(files, ".env."+profile, ".env."+profile+".local")
+	}
+	return append(files, ".env.local")
+}
+
+// Dotenv reads the dotenv files for profile into a stack, highest precedence
+// first.
+func Dotenv(profile string) (Stack, error) {
+	files := Files(profile)
+
+	var stack Stack
+	for i := len(files) - 1; i >= 0; i-- {
+		layer, err := ReadFile(files[i])
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, layer)
+	}
+
+	return stack, nil
+}
+
+// Env resolves variables from a stack of layers. A variable that is not set
+// directly can also be supplied through NAME_FILE, naming a file that holds
+// the value, or through a file called NAME in one of SecretsDirs.
+type Env struct {
+	Layers      Stack
+	SecretsDirs []string
+}
+
+// Origin describes where a resolved variable came from.
+type Origin struct {
+	// Layer is the layer that set the variable or its NAME_FILE companion.
+	Layer string
+	// Via is the NAME_FILE variable the value was read through, if any.
+	Via string
+	// File is the file the value was read from, if any.
+	File string
+}
+
+func (o Origin) String() string {
+	switch {
+	case o.Via != "":
+		return fmt.Sprintf("%s via %s (%s)", o.File, o.Via, o.Layer)
+	case o.File != "":
+		return o.File
+	default:
+		return o.Layer
+	}
+}
+
+// Resolve returns the value of the named variable and where it came from.
+// The error is non-nil when a NAME_FILE variable points at a file that
+// cannot be read.
+func (e Env) Resolve(name string) (string, Origin, bool, error) {
+	for _, layer := range e.Layers {
+		if value, ok := layer.Vars[name]; ok {
+			return value, Origin{Layer: layer.Name}, true, nil
+		}
+	}
+
+	fileVar := name + "_FILE"
+	for _, layer := range e.Layers {
+		if path, ok := layer.Vars[fileVar]; ok {
+			origin := Origin{Layer: layer.Name, Via: fileVar, File: path}
+			value, err := ReadSecretFile(path)
+			if err != nil {
+				return "", origin, false, fmt.Errorf("error reading %s: %w", fileVar, err)
+			}
+			return value, origin, true, nil
+		}
+	}
+
+	for _, dir := range e.SecretsDirs {
+		path := filepath.Join(dir, name)
+		value, err := ReadSecretFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", Origin{Layer: "secrets dir", File: path}, false, err
+		}
+		return value, Origin{Layer: "secrets dir", File: path}, true, nil
+	}
+
+	return "", Origin{}, false, nil
+}
+
+// ReadSecretFile reads a secret mounted as a file, dropping the trailing
+// newline most tools write.
+func ReadSecretFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
@@ -0,0 +1,420 @@
+// Package placeholder implements the ${VAR} placeholder grammar shared by the
+// config loader and configgen.
+//
+// Supported forms follow Bash parameter expansion:
+//
+//	${VAR}          value of VAR, empty if unset
+//	${VAR:-word}    word if VAR is unset or empty
+//	${VAR:word}     same as ${VAR:-word}
+//	${VAR-word}     word if VAR is unset
+//	${VAR:?message} error if VAR is unset or empty
+//	${VAR?message}  error if VAR is unset
+//	${VAR:+word}    word if VAR is set and not empty, otherwise empty
+//	${VAR+word}     word if VAR is set, otherwise empty
+//	$$              a literal $
+//
+// Words may themselves contain placeholders, e.g. ${A:-${B:-x}}.
+//
+// A lowercase name followed by a colon, as in ${file:/run/secrets/db}, is a
+// scheme reference. Its argument is resolved by a Schemes function rather
+// than looked up as a variable, so lowercase variable names cannot use the
+// legacy ${VAR:default} form.
+package placeholder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Op is the operator of a placeholder.
+type Op int
+
+const (
+	// OpNone is a bare ${VAR}.
+	OpNone Op = iota
+	// OpDefault is ${VAR:-word} or ${VAR:word}.
+	OpDefault
+	// OpDefaultUnset is ${VAR-word}.
+	OpDefaultUnset
+	// OpRequired is ${VAR:?message}.
+	OpRequired
+	// OpRequiredUnset is ${VAR?message}.
+	OpRequiredUnset
+	// OpAlt is ${VAR:+word}.
+	OpAlt
+	// OpAltUnset is ${VAR+word}.
+	OpAltUnset
+)
+
+// Lookup returns the value of a variable and whether it is set.
+type Lookup func(name string) (string, bool)
+
+// Schemes resolves a ${scheme:arg} reference.
+type Schemes func(scheme, arg string) (string, error)
+
+// Ref is a single ${...} placeholder.
+type Ref struct {
+	Name string
+	// Scheme is set for ${scheme:arg} references, in which case Name is
+	// empty and Word holds the argument.
+	Scheme string
+	Op     Op
+	// Word is the parsed default, alternate value or error message.
+	Word Template
+	// Raw is the unparsed text of Word.
+	Raw string
+}
+
+// Part is either literal text or a placeholder.
+type Part struct {
+	Literal string
+	Ref     *Ref
+}
+
+// Template is a parsed string.
+type Template []Part
+
+// UnsetError reports a placeholder that requires a variable which is not set.
+type UnsetError struct {
+	Name    string
+	Message string
+}
+
+func (e *UnsetError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s: %s", e.Name, e.Message)
+	}
+	return fmt.Sprintf("%s: required variable is not set", e.Name)
+}
+
+// Parse parses s into a template.
+func Parse(s string) (Template, error) {
+	p := parser{input: s}
+	t, err := p.parse(false)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Refs returns every placeholder in t, including nested ones, in the order
+// they appear.
+func (t Template) Refs() []*Ref {
+	var refs []*Ref
+	for _, part := range t {
+		if part.Ref != nil {
+			refs = append(refs, part.Ref)
+			refs = append(refs, part.Ref.Word.Refs()...)
+		}
+	}
+	return refs
+}
+
+// Vars returns the variable references in t, skipping scheme references.
+func (t Template) Vars() []*Ref {
+	var refs []*Ref
+	for _, ref := range t.Refs() {
+		if ref.Scheme == "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Expand substitutes every placeholder in t using lookup and schemes, which
+// may be nil when t has no scheme references. Placeholders that fail expand
+// to an empty string; all errors are joined into the returned error.
+func (t Template) Expand(lookup Lookup, schemes Schemes) (string, error) {
+	var b strings.Builder
+	var errs []error
+	for _, part := range t {
+		if part.Ref == nil {
+			b.WriteString(part.Literal)
+			continue
+		}
+
+		value, err := part.Ref.Expand(lookup, schemes)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		b.WriteString(value)
+	}
+	return b.String(), errors.Join(errs...)
+}
+
+// OnlyUnset reports whether err, as returned by Expand, consists solely of
+// *UnsetError values.
+func OnlyUnset(err error) bool {
+	if err == nil {
+		return true
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if !OnlyUnset(e) {
+				return false
+			}
+		}
+		return true
+	}
+	_, ok := err.(*UnsetError)
+	return ok
+}
+
+// Unresolved returns the required placeholders in t that lookup cannot
+// satisfy. Nested placeholders are only considered when Expand would
+// evaluate them, so ${A:-${B}} needs B only while A is unset.
+func (t Template) Unresolved(lookup Lookup) []*UnsetError {
+	var missing []*UnsetError
+	for _, part := range t {
+		if part.Ref != nil {
+			missing = append(missing, part.Ref.unresolved(lookup)...)
+		}
+	}
+	return missing
+}
+
+func (r *Ref) unresolved(lookup Lookup) []*UnsetError {
+	if r.Scheme != "" {
+		return r.Word.Unresolved(lookup)
+	}
+
+	value, set := lookup(r.Name)
+	nonEmpty := set && value != ""
+
+	switch r.Op {
+	case OpNone:
+		if !nonEmpty {
+			return []*UnsetError{{Name: r.Name}}
+		}
+	case OpDefault:
+		if !nonEmpty {
+			return r.Word.Unresolved(lookup)
+		}
+	case OpDefaultUnset:
+		if !set {
+			return r.Word.Unresolved(lookup)
+		}
+	case OpRequired, OpRequiredUnset:
+		if _, err := r.Expand(lookup, nil); err != nil {
+			var unset *UnsetError
+			if errors.As(err, &unset) {
+				return []*UnsetError{unset}
+			}
+		}
+	case OpAlt:
+		if nonEmpty {
+			return r.Word.Unresolved(lookup)
+		}
+	case OpAltUnset:
+		if set {
+			return r.Word.Unresolved(lookup)
+		}
+	}
+	return nil
+}
+
+// Expand resolves a single placeholder using lookup and schemes.
+func (r *Ref) Expand(lookup Lookup, schemes Schemes) (string, error) {
+	if r.Scheme != "" {
+		arg, err := r.Word.Expand(lookup, schemes)
+		if err != nil {
+			return "", err
+		}
+		if schemes == nil {
+			return "", fmt.Errorf("unsupported placeholder scheme %q; %s", r.Scheme, LegacyDefaultHint(r.Scheme, arg))
+		}
+		return schemes(r.Scheme, arg)
+	}
+
+	value, set := lookup(r.Name)
+	nonEmpty := set && value != ""
+
+	switch r.Op {
+	case OpDefault:
+		if !nonEmpty {
+			return r.Word.Expand(lookup, schemes)
+		}
+	case OpDefaultUnset:
+		if !set {
+			return r.Word.Expand(lookup, schemes)
+		}
+	case OpRequired, OpRequiredUnset:
+		if (r.Op == OpRequired && !nonEmpty) || (r.Op == OpRequiredUnset && !set) {
+			message, _ := r.Word.Expand(lookup, schemes)
+			return "", &UnsetError{Name: r.Name, Message: message}
+		}
+	case OpAlt:
+		if nonEmpty {
+			return r.Word.Expand(lookup, schemes)
+		}
+		return "", nil
+	case OpAltUnset:
+		if set {
+			return r.Word.Expand(lookup, schemes)
+		}
+		return "", nil
+	}
+
+	return value, nil
+}
+
+// Required reports whether the placeholder has no way to resolve without
+// its variable being set.
+func (r *Ref) Required() bool {
+	if r.Scheme != "" {
+		return false
+	}
+	return r.Op == OpNone || r.Op == OpRequired || r.Op == OpRequiredUnset
+}
+
+// HasDefault reports whether the placeholder supplies a fallback value when
+// its variable is unset.
+func (r *Ref) HasDefault() bool {
+	return r.Op == OpDefault || r.Op == OpDefaultUnset
+}
+
+// Default returns the fallback value of the placeholder with nested
+// placeholders resolved to their own defaults.
+func (r *Ref) Default() string {
+	if !r.HasDefault() {
+		return ""
+	}
+	value, err := r.Word.Expand(func(string) (string, bool) { return "", false }, nil)
+	if err != nil {
+		return r.Raw
+	}
+	return value
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) parse(inWord bool) (Template, error) {
+	var t Template
+	var literal strings.Builder
+
+	flush := func() {
+		if literal.Len() > 0 {
+			t = append(t, Part{Literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+
+		switch {
+		case inWord && c == '}':
+			flush()
+			return t, nil
+		case c == '$' && strings.HasPrefix(p.input[p.pos:], "$$"):
+			literal.WriteByte('$')
+			p.pos += 2
+		case c == '$' && strings.HasPrefix(p.input[p.pos:], "${"):
+			flush()
+			ref, err := p.parseRef()
+			if err != nil {
+				return nil, err
+			}
+			t = append(t, Part{Ref: ref})
+		default:
+			literal.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if inWord {
+		return nil, fmt.Errorf("unterminated placeholder in %q", p.input)
+	}
+
+	flush()
+	return t, nil
+}
+
+func (p *parser) parseRef() (*Ref, error) {
+	start := p.pos
+	p.pos += len("${")
+
+	nameStart := p.pos
+	for p.pos < len(p.input) && isNameByte(p.input[p.pos], p.pos == nameStart) {
+		p.pos++
+	}
+	if p.pos == nameStart {
+		return nil, fmt.Errorf("invalid placeholder name at offset %d in %q", start, p.input)
+	}
+
+	ref := &Ref{Name: p.input[nameStart:p.pos]}
+
+	rest := p.input[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, ":") && IsScheme(ref.Name):
+		ref.Scheme, ref.Name, p.pos = ref.Name, "", p.pos+1
+	case strings.HasPrefix(rest, "}"):
+		p.pos++
+		return ref, nil
+	case strings.HasPrefix(rest, ":-"):
+		ref.Op, p.pos = OpDefault, p.pos+2
+	case strings.HasPrefix(rest, ":?"):
+		ref.Op, p.pos = OpRequired, p.pos+2
+	case strings.HasPrefix(rest, ":+"):
+		ref.Op, p.pos = OpAlt, p.pos+2
+	case strings.HasPrefix(rest, ":"):
+		ref.Op, p.pos = OpDefault, p.pos+1
+	case strings.HasPrefix(rest, "-"):
+		ref.Op, p.pos = OpDefaultUnset, p.pos+1
+	case strings.HasPrefix(rest, "?"):
+		ref.Op, p.pos = OpRequiredUnset, p.pos+1
+	case strings.HasPrefix(rest, "+"):
+		ref.Op, p.pos = OpAltUnset, p.pos+1
+	case rest == "":
+		return nil, fmt.Errorf("unterminated placeholder at offset %d in %q", start, p.input)
+	default:
+		return nil, fmt.Errorf("invalid placeholder name at offset %d in %q", start, p.input)
+	}
+
+	wordStart := p.pos
+	word, err := p.parse(true)
+	if err != nil {
+		return nil, err
+	}
+	ref.Word = word
+	ref.Raw = p.input[wordStart:p.pos]
+	p.pos++
+
+	return ref, nil
+}
+
+// LegacyDefaultHint explains how to rewrite ${name:word}, which older
+// versions read as a default for the lowercase variable name and which now
+// refers to the scheme name.
+func LegacyDefaultHint(name, word string) string {
+	return fmt.Sprintf("if ${%s:%s} is meant as a default, write ${%s:-%s}", name, word, name, word)
+}
+
+// IsScheme reports whether name is a valid scheme: a lowercase letter followed
+// by lowercase letters or digits.
+func IsScheme(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !('a' <= c && c <= 'z') && !(i > 0 && '0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isNameByte(c byte, first bool) bool {
+	switch {
+	case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case '0' <= c && c <= '9':
+		return !first
+	}
+	return false
+}
@@ -0,0 +1,282 @@
+package placeholder
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// unsetError marks an expansion that must fail with an *UnsetError.
+const unsetError = "<unset>"
+
+func lookupFrom(vars map[string]string) Lookup {
+	return func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+func TestExpandOperators(t *testing.T) {
+	tests := []struct {
+		input             string
+		unset, empty, set string
+	}{
+		{"${VAR}", "", "", "v"},
+		{"${VAR:-word}", "word", "word", "v"},
+		{"${VAR:word}", "word", "word", "v"},
+		{"${VAR-word}", "word", "", "v"},
+		{"${VAR:?message}", unsetError, unsetError, "v"},
+		{"${VAR?message}", unsetError, "", "v"},
+		{"${VAR:+word}", "", "", "word"},
+		{"${VAR+word}", "", "word", "word"},
+		{"a-${VAR:-b}-c", "a-b-c", "a-b-c", "a-v-c"},
+		{"${VAR:-}", "", "", "v"},
+	}
+
+	states := []struct {
+		name string
+		vars map[string]string
+	}{
+		{"unset", map[string]string{}},
+		{"empty", map[string]string{"VAR": ""}},
+		{"set", map[string]string{"VAR": "v"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tmpl, err := Parse(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, want := range []string{tt.unset, tt.empty, tt.set} {
+				got, err := tmpl.Expand(lookupFrom(states[i].vars), nil)
+				if want == unsetError {
+					var unset *UnsetError
+					if !errors.As(err, &unset) || unset.Name != "VAR" || unset.Message != "message" {
+						t.Errorf("%s: err = %v, want an UnsetError for VAR", states[i].name, err)
+					}
+					continue
+				}
+				if err != nil || got != want {
+					t.Errorf("%s: Expand = %q, %v; want %q", states[i].name, got, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandNesting(t *testing.T) {
+	tests := []struct {
+		input string
+		vars  map[string]string
+		want  string
+	}{
+		{"${A:-${B:-x}}", map[string]string{}, "x"},
+		{"${A:-${B:-x}}", map[string]string{"B": "b"}, "b"},
+		{"${A:-${B:-x}}", map[string]string{"A": "a", "B": "b"}, "a"},
+		{"${A:+<${B}>}", map[string]string{"A": "a", "B": "b"}, "<b>"},
+		{"${A:-x${B:-y}z}", map[string]string{}, "xyz"},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		if got, err := tmpl.Expand(lookupFrom(tt.vars), nil); err != nil || got != tt.want {
+			t.Errorf("Expand(%q, %v) = %q, %v; want %q", tt.input, tt.vars, got, err, tt.want)
+		}
+	}
+}
+
+func TestExpandDollar(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"$$", "$"},
+		{"a$$b", "a$b"},
+		{"$${VAR}", "${VAR}"},
+		{"$$$${VAR}", "$${VAR}"},
+		{"$VAR", "$VAR"},
+		{"cost: 5$", "cost: 5$"},
+		{"${A:-$$}", "$"},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		if got, err := tmpl.Expand(lookupFrom(map[string]string{"VAR": "v"}), nil); err != nil || got != tt.want {
+			t.Errorf("Expand(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestSchemes(t *testing.T) {
+	tmpl, err := Parse("${file:/run/${NAME:-db}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := tmpl.Refs()
+	if len(refs) != 2 || refs[0].Scheme != "file" || refs[0].Name != "" || refs[1].Name != "NAME" {
+		t.Fatalf("Refs = %+v, want a file scheme wrapping NAME", refs)
+	}
+	if vars := tmpl.Vars(); len(vars) != 1 || vars[0].Name != "NAME" {
+		t.Errorf("Vars = %+v, want only NAME", vars)
+	}
+	if refs[0].Required() {
+		t.Error("scheme reference reported as required")
+	}
+
+	var gotScheme, gotArg string
+	schemes := func(scheme, arg string) (string, error) {
+		gotScheme, gotArg = scheme, arg
+		return "secret", nil
+	}
+	got, err := tmpl.Expand(lookupFrom(map[string]string{}), schemes)
+	if err != nil || got != "secret" || gotScheme != "file" || gotArg != "/run/db" {
+		t.Errorf("Expand = %q, %v with %s:%s; want secret with file:/run/db", got, err, gotScheme, gotArg)
+	}
+
+	if _, err := tmpl.Expand(lookupFrom(map[string]string{}), nil); err == nil {
+		t.Error("Expand without schemes succeeded")
+	}
+
+	// Lowercase names used to take the legacy ${name:default} form; the
+	// error says how to write the default now.
+	lower, err := Parse("${name:anon}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lower.Expand(lookupFrom(map[string]string{}), nil); err == nil || !strings.Contains(err.Error(), "${name:-anon}") {
+		t.Errorf("Expand(${name:anon}) error = %v, want a hint for ${name:-anon}", err)
+	}
+
+	// Upper-case names keep the legacy ${VAR:default} form.
+	legacy, err := Parse("${FILE:/run/db}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref := legacy[0].Ref; ref.Scheme != "" || ref.Name != "FILE" || ref.Op != OpDefault {
+		t.Errorf("Parse(${FILE:/run/db}) = %+v, want a FILE default", ref)
+	}
+}
+
+func TestIsScheme(t *testing.T) {
+	for name, want := range map[string]bool{
+		"":      false,
+		"file":  true,
+		"s3":    true,
+		"File":  false,
+		"FILE":  false,
+		"3s":    false,
+		"my_fn": false,
+	} {
+		if got := IsScheme(name); got != want {
+			t.Errorf("IsScheme(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"${", "invalid placeholder name"},
+		{"${VAR", "unterminated placeholder"},
+		{"${VAR:-word", "unterminated placeholder"},
+		{"${A:-${B}", "unterminated placeholder"},
+		{"${}", "invalid placeholder name"},
+		{"${1VAR}", "invalid placeholder name"},
+		{"${VAR!}", "invalid placeholder name"},
+		{"${ VAR}", "invalid placeholder name"},
+	}
+
+	for _, tt := range tests {
+		if _, err := Parse(tt.input); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestUnresolved(t *testing.T) {
+	tests := []struct {
+		input string
+		vars  map[string]string
+		want  []string
+	}{
+		{"${A}", map[string]string{}, []string{"A"}},
+		{"${A}", map[string]string{"A": ""}, []string{"A"}},
+		{"${A}", map[string]string{"A": "a"}, nil},
+		{"${A:-x}", map[string]string{}, nil},
+		{"${A:-${B}}", map[string]string{}, []string{"B"}},
+		{"${A:-${B}}", map[string]string{"A": "a"}, nil},
+		{"${A?}", map[string]string{"A": ""}, nil},
+		{"${A:?}", map[string]string{"A": ""}, []string{"A"}},
+		{"${A:+${B}}", map[string]string{"A": "a"}, []string{"B"}},
+		{"${A:+${B}}", map[string]string{}, nil},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+
+		var got []string
+		for _, unset := range tmpl.Unresolved(lookupFrom(tt.vars)) {
+			got = append(got, unset.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Unresolved(%q, %v) = %v, want %v", tt.input, tt.vars, got, tt.want)
+		}
+	}
+}
+
+func TestOnlyUnset(t *testing.T) {
+	tmpl, err := Parse("${A:?a} ${B:?b}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tmpl.Expand(lookupFrom(map[string]string{}), nil)
+	if err == nil || !OnlyUnset(err) {
+		t.Errorf("OnlyUnset(%v) = false, want true", err)
+	}
+
+	tmpl, err = Parse("${A:?a} ${file:x}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tmpl.Expand(lookupFrom(map[string]string{}), nil)
+	if OnlyUnset(err) {
+		t.Errorf("OnlyUnset(%v) = true, want false", err)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"${A:-x}", "x"},
+		{"${A-x}", "x"},
+		{"${A:-${B:-y}}", "y"},
+		{"${A}", ""},
+		{"${A:+x}", ""},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		if got := tmpl[0].Ref.Default(); got != tt.want {
+			t.Errorf("Default(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
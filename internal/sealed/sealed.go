@@ -0,0 +1,231 @@
+// Package sealed encrypts individual config values into sops-style
+// ENC[...] strings, so that config.yaml can be committed with its secrets
+// encrypted in place.
+//
+// Two key kinds are supported. A key file holding a base64-encoded 32-byte
+// key produces ENC[AES256_GCM,data:...,iv:...,tag:...,type:...] values,
+// authenticated with the YAML path of the value so ciphertexts cannot be
+// moved between fields. A file of age identities (AGE-SECRET-KEY-...)
+// produces ENC[AGE,data:...,type:...] values.
+package sealed
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"filippo.io/age"
+)
+
+const (
+	algAES = "AES256_GCM"
+	algAge = "AGE"
+)
+
+var encPattern = regexp.MustCompile(`^ENC\[([A-Z0-9_]+),(.*)\]$`)
+
+// IsEncrypted reports whether value is an ENC[...] string.
+func IsEncrypted(value string) bool {
+	return encPattern.MatchString(value)
+}
+
+// Keyring holds the key used to encrypt and decrypt values.
+type Keyring struct {
+	aesKey     []byte
+	identities []age.Identity
+	recipients []age.Recipient
+}
+
+// ReadKeyFile loads a keyring from a key file or an age identity file.
+func ReadKeyFile(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file: %w", err)
+	}
+
+	k, err := ParseKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing key file %s: %w", path, err)
+	}
+	return k, nil
+}
+
+// ParseKey parses the contents of a key file or an age identity file.
+func ParseKey(data []byte) (*Keyring, error) {
+	if bytes.Contains(data, []byte("AGE-SECRET-KEY-")) {
+		identities, err := age.ParseIdentities(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		k := &Keyring{identities: identities}
+		for _, identity := range identities {
+			if x, ok := identity.(*age.X25519Identity); ok {
+				k.recipients = append(k.recipients, x.Recipient())
+			}
+		}
+		return k, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("key is neither base64 nor an age identity: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	return &Keyring{aesKey: key}, nil
+}
+
+// GenerateKey returns the contents of a new AES-256 key file.
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key) + "\n", nil
+}
+
+// GenerateAgeIdentity returns the contents of a new age identity file.
+func GenerateAgeIdentity() (string, error) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("# public key: %s\n%s\n", identity.Recipient(), identity), nil
+}
+
+// Encrypt seals plaintext, the value found at the YAML path, recording its
+// YAML type (str, int, float or bool) so it can be restored on decryption.
+func (k *Keyring) Encrypt(path, plaintext, typ string) (string, error) {
+	if len(k.recipients) > 0 {
+		var buf bytes.Buffer
+		w, err := age.Encrypt(&buf, k.recipients...)
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.WriteString(w, plaintext); err != nil {
+			return "", err
+		}
+		if err := w.Close(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("ENC[%s,data:%s,type:%s]", algAge, encode(buf.Bytes()), typ), nil
+	}
+
+	if k.aesKey == nil {
+		return "", fmt.Errorf("keyring has no encryption key")
+	}
+
+	gcm, err := k.gcm()
+	if err != nil {
+		return "", err
+	}
+
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nil, iv, []byte(plaintext), []byte(path))
+	data, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return fmt.Sprintf("ENC[%s,data:%s,iv:%s,tag:%s,type:%s]", algAES, encode(data), encode(iv), encode(tag), typ), nil
+}
+
+// Decrypt opens an ENC[...] value found at the YAML path and returns the
+// plaintext and its YAML type.
+func (k *Keyring) Decrypt(path, value string) (string, string, error) {
+	match := encPattern.FindStringSubmatch(value)
+	if match == nil {
+		return "", "", fmt.Errorf("not an encrypted value")
+	}
+
+	alg := match[1]
+	fields := make(map[string]string)
+	for _, field := range strings.Split(match[2], ",") {
+		name, val, _ := strings.Cut(field, ":")
+		fields[name] = val
+	}
+
+	data, err := decode(fields["data"])
+	if err != nil {
+		return "", "", fmt.Errorf("invalid data: %w", err)
+	}
+
+	typ := fields["type"]
+	if typ == "" {
+		typ = "str"
+	}
+
+	switch alg {
+	case algAES:
+		if k.aesKey == nil {
+			return "", "", fmt.Errorf("an AES key is required to decrypt %s", path)
+		}
+
+		iv, err := decode(fields["iv"])
+		if err != nil {
+			return "", "", fmt.Errorf("invalid iv: %w", err)
+		}
+		tag, err := decode(fields["tag"])
+		if err != nil {
+			return "", "", fmt.Errorf("invalid tag: %w", err)
+		}
+
+		gcm, err := k.gcm()
+		if err != nil {
+			return "", "", err
+		}
+		if len(iv) != gcm.NonceSize() {
+			return "", "", fmt.Errorf("invalid iv length")
+		}
+
+		plaintext, err := gcm.Open(nil, iv, append(data, tag...), []byte(path))
+		if err != nil {
+			return "", "", fmt.Errorf("cannot decrypt %s: wrong key or value moved from another path", path)
+		}
+		return string(plaintext), typ, nil
+
+	case algAge:
+		if len(k.identities) == 0 {
+			return "", "", fmt.Errorf("an age identity is required to decrypt %s", path)
+		}
+
+		r, err := age.Decrypt(bytes.NewReader(data), k.identities...)
+		if err != nil {
+			return "", "", fmt.Errorf("cannot decrypt %s: %w", path, err)
+		}
+		plaintext, err := io.ReadAll(r)
+		if err != nil {
+			return "", "", err
+		}
+		return string(plaintext), typ, nil
+
+	default:
+		return "", "", fmt.Errorf("unsupported encryption %q", alg)
+	}
+}
+
+func (k *Keyring) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k.aesKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encode(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(s)
+}
@@ -0,0 +1,134 @@
+package sealed
+
+import (
+	"strings"
+	"testing"
+)
+
+func newKeyrings(t *testing.T) map[string]func() *Keyring {
+	t.Helper()
+	return map[string]func() *Keyring{
+		"aes": func() *Keyring {
+			key, err := GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			k, err := ParseKey([]byte(key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return k
+		},
+		"age": func() *Keyring {
+			identity, err := GenerateAgeIdentity()
+			if err != nil {
+				t.Fatal(err)
+			}
+			k, err := ParseKey([]byte(identity))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return k
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for name, newKeyring := range newKeyrings(t) {
+		t.Run(name, func(t *testing.T) {
+			k := newKeyring()
+			for _, tt := range []struct{ plaintext, typ string }{
+				{"hunter2", "str"},
+				{"", "str"},
+				{"a,b:c]", "str"},
+				{"12345", "int"},
+				{"true", "bool"},
+			} {
+				value, err := k.Encrypt("database.password", tt.plaintext, tt.typ)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !IsEncrypted(value) {
+					t.Errorf("IsEncrypted(%q) = false", value)
+				}
+				if strings.Contains(value, tt.plaintext) && tt.plaintext != "" {
+					t.Errorf("Encrypt(%q) = %q leaks the plaintext", tt.plaintext, value)
+				}
+
+				plaintext, typ, err := k.Decrypt("database.password", value)
+				if err != nil || plaintext != tt.plaintext || typ != tt.typ {
+					t.Errorf("Decrypt = %q, %q, %v; want %q, %q", plaintext, typ, err, tt.plaintext, tt.typ)
+				}
+			}
+		})
+	}
+}
+
+func TestDecryptWrongPath(t *testing.T) {
+	k := newKeyrings(t)["aes"]()
+	value, err := k.Encrypt("database.password", "hunter2", "str")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := k.Decrypt("cache.redis.password", value); err == nil {
+		t.Error("Decrypt at another path succeeded")
+	}
+}
+
+func TestDecryptWrongKey(t *testing.T) {
+	for name, newKeyring := range newKeyrings(t) {
+		t.Run(name, func(t *testing.T) {
+			value, err := newKeyring().Encrypt("database.password", "hunter2", "str")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := newKeyring().Decrypt("database.password", value); err == nil {
+				t.Error("Decrypt with another key succeeded")
+			}
+		})
+	}
+}
+
+func TestDecryptWrongKeyKind(t *testing.T) {
+	keyrings := newKeyrings(t)
+	aesKeys, ageKeys := keyrings["aes"](), keyrings["age"]()
+
+	value, err := aesKeys.Encrypt("database.password", "hunter2", "str")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ageKeys.Decrypt("database.password", value); err == nil || !strings.Contains(err.Error(), "AES key is required") {
+		t.Errorf("Decrypt of an AES value with an age identity: err = %v", err)
+	}
+
+	value, err = ageKeys.Encrypt("database.password", "hunter2", "str")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := aesKeys.Decrypt("database.password", value); err == nil || !strings.Contains(err.Error(), "age identity is required") {
+		t.Errorf("Decrypt of an age value with an AES key: err = %v", err)
+	}
+}
+
+func TestDecryptMalformed(t *testing.T) {
+	k := newKeyrings(t)["aes"]()
+	for _, value := range []string{
+		"hunter2",
+		"ENC[AES256_GCM,data:!!!,iv:,tag:,type:str]",
+		"ENC[AES256_GCM,data:,iv:AAAA,tag:,type:str]",
+		"ENC[ROT13,data:,type:str]",
+	} {
+		if _, _, err := k.Decrypt("database.password", value); err == nil {
+			t.Errorf("Decrypt(%q) succeeded", value)
+		}
+	}
+}
+
+func TestParseKeyErrors(t *testing.T) {
+	for _, data := range []string{"not base64!", "c2hvcnQ=", "AGE-SECRET-KEY-INVALID"} {
+		if _, err := ParseKey([]byte(data)); err == nil {
+			t.Errorf("ParseKey(%q) succeeded", data)
+		}
+	}
+}
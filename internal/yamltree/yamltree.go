@@ -0,0 +1,144 @@
+// Package yamltree walks parsed YAML documents by dotted path and reads the
+// "# @directive" annotations attached to their keys.
+package yamltree
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Directive is a single "@name args" annotation taken from a YAML comment.
+type Directive struct {
+	Name string
+	Args string
+}
+
+// Directives is the list of annotations attached to one key.
+type Directives []Directive
+
+// ParseDirectives extracts the directives from a YAML comment block. Comment
+// lines that do not start with @ are ignored.
+func ParseDirectives(comment string) Directives {
+	var directives Directives
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+
+		name, args, _ := strings.Cut(line[1:], " ")
+		if name != "" {
+			directives = append(directives, Directive{Name: name, Args: strings.TrimSpace(args)})
+		}
+	}
+	return directives
+}
+
+// Lookup returns the first directive called name.
+func (d Directives) Lookup(name string) (Directive, bool) {
+	for _, directive := range d {
+		if directive.Name == name {
+			return directive, true
+		}
+	}
+	return Directive{}, false
+}
+
+// Has reports whether a directive called name is present.
+func (d Directives) Has(name string) bool {
+	_, ok := d.Lookup(name)
+	return ok
+}
+
+// Entry is a mapping entry or sequence item visited by Walk.
+type Entry struct {
+	// Path is the dotted path of the entry, with sequence items addressed
+	// by index.
+	Path string
+	// Key is the mapping key node, nil for sequence items.
+	Key   *yaml.Node
+	Value *yaml.Node
+	// Directives are the annotations written on this entry itself.
+	Directives Directives
+	Parent     *Entry
+}
+
+// Inherited returns the nearest directive called name on e or one of its
+// ancestors.
+func (e *Entry) Inherited(name string) (Directive, bool) {
+	for cur := e; cur != nil; cur = cur.Parent {
+		if directive, ok := cur.Directives.Lookup(name); ok {
+			return directive, true
+		}
+	}
+	return Directive{}, false
+}
+
+// All returns every directive called name on e and its ancestors, innermost
+// first.
+func (e *Entry) All(name string) Directives {
+	var directives Directives
+	for cur := e; cur != nil; cur = cur.Parent {
+		for _, directive := range cur.Directives {
+			if directive.Name == name {
+				directives = append(directives, directive)
+			}
+		}
+	}
+	return directives
+}
+
+// IsLeaf reports whether the entry holds a scalar value.
+func (e *Entry) IsLeaf() bool {
+	return e.Value.Kind == yaml.ScalarNode
+}
+
+// Walk calls fn for every entry below root in document order, parents before
+// their children.
+func Walk(root *yaml.Node, fn func(e *Entry)) {
+	walk(root, nil, fn)
+}
+
+func walk(node *yaml.Node, parent *Entry, fn func(e *Entry)) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			walk(child, parent, fn)
+		}
+	case yaml.AliasNode:
+		walk(node.Alias, parent, fn)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			e := &Entry{
+				Path:       join(parent, key.Value),
+				Key:        key,
+				Value:      value,
+				Directives: ParseDirectives(key.HeadComment + "\n" + key.LineComment + "\n" + value.LineComment),
+				Parent:     parent,
+			}
+			fn(e)
+			walk(value, e, fn)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			e := &Entry{
+				Path:       join(parent, strconv.Itoa(i)),
+				Value:      item,
+				Directives: ParseDirectives(item.HeadComment + "\n" + item.LineComment),
+				Parent:     parent,
+			}
+			fn(e)
+			walk(item, e, fn)
+		}
+	}
+}
+
+func join(parent *Entry, key string) string {
+	if parent == nil {
+		return key
+	}
+	return parent.Path + "." + key
+}
@@ -22,7 +22,7 @@ func main() {
 	fmt.Printf("  Version: %s\n", cfg.Game.Version)
 	fmt.Printf("  Max Players: %d\n", cfg.Game.MaxPlayers)
 	fmt.Printf("  Difficulty: %s\n", cfg.Game.Difficulty)
-	fmt.Printf("  PvP Enabled: %t\n", cfg.Game.PvpEnabled)
+	fmt.Printf("  PvP Enabled: %t\n", cfg.Game.PVPEnabled)
 
 	// Display World Configuration
 	fmt.Printf("\n🌍 World Settings:\n")
@@ -48,11 +48,11 @@ func main() {
 	fmt.Printf("\n🌐 Web Server Settings:\n")
 	fmt.Printf("  Host: %s\n", cfg.Web.Host)
 	fmt.Printf("  Port: %s\n", cfg.Web.Port)
-	fmt.Printf("  SSL Enabled: %s\n", cfg.Web.SslEnabled)
+	fmt.Printf("  SSL Enabled: %s\n", cfg.Web.SSLEnabled)
 	fmt.Printf("  Admin Panel: %t\n", cfg.Web.AdminPanel)
-	fmt.Printf("  API Rate Limit: %d\n", cfg.Web.Api.RateLimit)
-	fmt.Printf("  API Timeout: %s\n", cfg.Web.Api.Timeout)
-	fmt.Printf("  CORS Enabled: %t\n", cfg.Web.Api.CorsEnabled)
+	fmt.Printf("  API Rate Limit: %d\n", cfg.Web.API.RateLimit)
+	fmt.Printf("  API Timeout: %s\n", cfg.Web.API.Timeout)
+	fmt.Printf("  CORS Enabled: %t\n", cfg.Web.API.CORSEnabled)
 
 	// Display Database Configuration
 	fmt.Printf("\n🗄️  Database Settings:\n")
@@ -77,8 +77,8 @@ func main() {
 	fmt.Printf("  Type: %s\n", cfg.Cache.Type)
 	fmt.Printf("  Redis Host: %s\n", cfg.Cache.Redis.Host)
 	fmt.Printf("  Redis Port: %s\n", cfg.Cache.Redis.Port)
-	fmt.Printf("  Player Data TTL: %s\n", cfg.Cache.Ttl.PlayerData)
-	fmt.Printf("  Leaderboards TTL: %s\n", cfg.Cache.Ttl.Leaderboards)
+	fmt.Printf("  Player Data TTL: %s\n", cfg.Cache.TTL.PlayerData)
+	fmt.Printf("  Leaderboards TTL: %s\n", cfg.Cache.TTL.Leaderboards)
 
 	// Display Security Configuration
 	fmt.Printf("\n🔒 Security Settings:\n")
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"project/internal/placeholder"
+	"project/internal/sealed"
+)
+
+// unsetLookup resolves every variable as unset, so placeholders fall back to
+// their defaults.
+func unsetLookup(string) (string, bool) {
+	return "", false
+}
+
+// defaultValue returns the value a leaf has when no variables are set:
+// literals as written and placeholders replaced by their defaults. Values
+// that cannot be built without a variable or a scheme reference, and
+// encrypted values, yield "".
+func defaultValue(raw string) string {
+	if sealed.IsEncrypted(raw) {
+		return ""
+	}
+
+	t, err := placeholder.Parse(raw)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to parse placeholders: %v", err))
+	}
+	if len(t.Unresolved(unsetLookup)) > 0 {
+		return ""
+	}
+	value, err := t.Expand(unsetLookup, nil)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// goDefault returns the Go expression for a leaf's default value, or "" when
+// the default is the zero value of goType or does not parse as it.
+func goDefault(node *YamlNode, goType string) string {
+	if items, ok := node.Value.([]interface{}); ok {
+		var values []string
+		for _, item := range items {
+			values = append(values, strconv.Quote(defaultValue(fmt.Sprint(item))))
+		}
+		if len(values) == 0 {
+			return ""
+		}
+		return "[]string{" + strings.Join(values, ", ") + "}"
+	}
+
+	if node.Value == nil {
+		return ""
+	}
+	value := defaultValue(fmt.Sprint(node.Value))
+
+	switch goType {
+	case "int":
+		if n, err := strconv.Atoi(value); err == nil && n != 0 {
+			return strconv.Itoa(n)
+		}
+	case "float64":
+		if f, err := strconv.ParseFloat(value, 64); err == nil && f != 0 {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	case "bool":
+		if b, err := strconv.ParseBool(value); err == nil && b {
+			return "true"
+		}
+	case "Secret":
+		if value != "" {
+			return "NewSecret(" + strconv.Quote(value) + ")"
+		}
+	default:
+		if value != "" {
+			return strconv.Quote(value)
+		}
+	}
+	return ""
+}
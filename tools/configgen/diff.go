@@ -0,0 +1,292 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"project/internal/sealed"
+	"project/internal/yamltree"
+)
+
+const redacted = "[REDACTED]"
+
+// settingChange is one leaf that differs between two config files. Old is
+// nil for added settings and New is nil for removed ones.
+type settingChange struct {
+	Path            string  `json:"path"`
+	Old             *string `json:"old,omitempty"`
+	New             *string `json:"new,omitempty"`
+	Secret          bool    `json:"secret"`
+	RestartRequired bool    `json:"restart_required"`
+	// NotCompared is set for encrypted values that could not be decrypted,
+	// whose ciphertexts differ whether or not their plaintexts do.
+	NotCompared bool `json:"not_compared,omitempty"`
+}
+
+// fileLeaf is a leaf setting read from a config file.
+type fileLeaf struct {
+	value  string
+	secret bool
+	line   int
+}
+
+func diffCommand(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := fs.String("format", "text", "output format: text, json or markdown")
+	keyFile := fs.String("key", os.Getenv("CONFIG_KEY_FILE"), "AES key file or age identity file for encrypted values")
+	newKeyFile := fs.String("new-key", "", "key file for the second file, if it differs from --key")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fmt.Println("Usage: configgen diff [--format text|json|markdown] [--key <key-file>] [--new-key <key-file>] <a.yaml> <b.yaml>")
+		os.Exit(1)
+	}
+
+	oldKeys := readOptionalKeyring(*keyFile)
+	newKeys := oldKeys
+	if *newKeyFile != "" {
+		newKeys = readOptionalKeyring(*newKeyFile)
+	}
+
+	changes := diffFiles(fs.Arg(0), fs.Arg(1), templateFieldMeta(), oldKeys, newKeys)
+
+	switch *format {
+	case "text":
+		printDiffText(changes)
+	case "json":
+		printDiffJSON(changes)
+	case "markdown":
+		printDiffMarkdown(changes)
+	default:
+		fmt.Printf("Unknown format: %s\n", *format)
+		os.Exit(1)
+	}
+}
+
+// templateFieldMeta returns the leaf metadata of the template, the same
+// metadata generate emits into config.go.
+func templateFieldMeta() []FieldMeta {
+	return collectFieldMeta(readTemplateTree())
+}
+
+// readOptionalKeyring reads the keyring in keyFile, or returns nil when no
+// key file is given.
+func readOptionalKeyring(keyFile string) *sealed.Keyring {
+	if keyFile == "" {
+		return nil
+	}
+	keyring, err := sealed.ReadKeyFile(keyFile)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	return keyring
+}
+
+// diffFiles compares the leaves of two config files as written, without
+// expanding placeholders. Encrypted values are compared after decryption
+// with oldKeys and newKeys, which may be nil; those that cannot be
+// decrypted are reported as not compared when their ciphertexts differ.
+// Settings known to the template come first, in template order, followed
+// by any others in file order.
+func diffFiles(a, b string, fields []FieldMeta, oldKeys, newKeys *sealed.Keyring) []settingChange {
+	oldLeaves, oldOrder := readLeaves(a)
+	newLeaves, newOrder := readLeaves(b)
+
+	meta := make(map[string]FieldMeta, len(fields))
+	var order []string
+	for _, f := range fields {
+		meta[f.Path] = f
+		order = append(order, f.Path)
+	}
+	for _, path := range append(oldOrder, newOrder...) {
+		if _, ok := meta[path]; !ok {
+			meta[path] = FieldMeta{Path: path}
+			order = append(order, path)
+		}
+	}
+
+	var changes []settingChange
+	for _, path := range order {
+		oldLeaf, inOld := oldLeaves[path]
+		newLeaf, inNew := newLeaves[path]
+		if inOld == inNew && oldLeaf.value == newLeaf.value {
+			continue
+		}
+
+		notCompared := false
+		if inOld && inNew {
+			oldValue, oldOK := plainValue(path, oldLeaf, oldKeys)
+			newValue, newOK := plainValue(path, newLeaf, newKeys)
+			if oldOK && newOK && oldValue == newValue {
+				continue
+			}
+			notCompared = !oldOK || !newOK
+		}
+
+		f := meta[path]
+		change := settingChange{
+			Path:            path,
+			Secret:          f.Secret || oldLeaf.secret || newLeaf.secret,
+			RestartRequired: f.Reload == "restart",
+			NotCompared:     notCompared,
+		}
+		if inOld {
+			change.Old = displayValue(oldLeaf.value, change.Secret)
+		}
+		if inNew {
+			change.New = displayValue(newLeaf.value, change.Secret)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// readLeaves returns the leaf values of a config file keyed by path, and the
+// paths in document order. Sequences are compared as a whole.
+func readLeaves(filename string) (map[string]fileLeaf, []string) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to read %s: %v", filename, err))
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		panic(fmt.Sprintf("Failed to parse %s: %v", filename, err))
+	}
+
+	leaves := make(map[string]fileLeaf)
+	var order []string
+	yamltree.Walk(&root, func(e *yamltree.Entry) {
+		for cur := e; cur != nil; cur = cur.Parent {
+			if cur.Key == nil {
+				return
+			}
+		}
+
+		value := e.Value
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+
+		var leaf fileLeaf
+		switch value.Kind {
+		case yaml.ScalarNode:
+			leaf = fileLeaf{
+				value:  value.Value,
+				secret: sealed.IsEncrypted(value.Value) || isSecret(e, extractEnvVarsFromString(value.Value)),
+			}
+		case yaml.SequenceNode:
+			var items interface{}
+			if err := value.Decode(&items); err != nil {
+				panic(fmt.Sprintf("Failed to decode %s: %v", e.Path, err))
+			}
+			encoded, _ := json.Marshal(items)
+			leaf = fileLeaf{value: string(encoded)}
+		default:
+			return
+		}
+
+		leaf.line = value.Line
+		leaves[e.Path] = leaf
+		order = append(order, e.Path)
+	})
+
+	return leaves, order
+}
+
+// plainValue returns the value of leaf, decrypted with keys if it is
+// encrypted, and false if it cannot be decrypted.
+func plainValue(path string, leaf fileLeaf, keys *sealed.Keyring) (string, bool) {
+	if !sealed.IsEncrypted(leaf.value) {
+		return leaf.value, true
+	}
+	if keys == nil {
+		return "", false
+	}
+	value, _, err := keys.Decrypt(path, leaf.value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+func displayValue(value string, secret bool) *string {
+	if secret && value != "" {
+		value = redacted
+	}
+	return &value
+}
+
+func printDiffText(changes []settingChange) {
+	if len(changes) == 0 {
+		fmt.Println("✅ No differences")
+		return
+	}
+
+	for _, c := range changes {
+		var line string
+		switch {
+		case c.Old == nil:
+			line = fmt.Sprintf("+ %s: %s", c.Path, *c.New)
+		case c.New == nil:
+			line = fmt.Sprintf("- %s: %s", c.Path, *c.Old)
+		case c.NotCompared:
+			line = fmt.Sprintf("? %s: encrypted (not compared)", c.Path)
+		default:
+			line = fmt.Sprintf("~ %s: %s -> %s", c.Path, *c.Old, *c.New)
+		}
+		if c.RestartRequired {
+			line += " (restart required)"
+		}
+		fmt.Println(line)
+	}
+}
+
+func printDiffJSON(changes []settingChange) {
+	if changes == nil {
+		changes = []settingChange{}
+	}
+
+	out, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(out))
+}
+
+func printDiffMarkdown(changes []settingChange) {
+	if len(changes) == 0 {
+		fmt.Println("No differences.")
+		return
+	}
+
+	fmt.Println("| Setting | Old | New | Notes |")
+	fmt.Println("|---|---|---|---|")
+	for _, c := range changes {
+		var notes []string
+		if c.Secret {
+			notes = append(notes, "secret")
+		}
+		if c.NotCompared {
+			notes = append(notes, "encrypted (not compared)")
+		}
+		if c.RestartRequired {
+			notes = append(notes, "restart required")
+		}
+		fmt.Printf("| `%s` | %s | %s | %s |\n", c.Path, markdownCell(c.Old), markdownCell(c.New), strings.Join(notes, ", "))
+	}
+}
+
+func markdownCell(value *string) string {
+	if value == nil {
+		return "—"
+	}
+	return "`" + strings.ReplaceAll(*value, "|", "\\|") + "`"
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"project/internal/sealed"
+)
+
+func newTestKeyring(t *testing.T) *sealed.Keyring {
+	t.Helper()
+	key, err := sealed.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := sealed.ParseKey([]byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring
+}
+
+func TestDiffFilesEncrypted(t *testing.T) {
+	oldKeys, newKeys := newTestKeyring(t), newTestKeyring(t)
+	encrypt := func(keys *sealed.Keyring, path, value string) string {
+		t.Helper()
+		encrypted, err := keys.Encrypt(path, value, "str")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return encrypted
+	}
+
+	dir := t.TempDir()
+	write := func(name, same, changed string) string {
+		t.Helper()
+		content := "auth:\n  same: " + same + "\n  changed: " + changed + "\n"
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+
+	a := write("a.yaml", encrypt(oldKeys, "auth.same", "s"), encrypt(oldKeys, "auth.changed", "old"))
+	// Encrypting again draws a new IV, so every ciphertext differs.
+	reencrypted := write("reencrypted.yaml", encrypt(oldKeys, "auth.same", "s"), encrypt(oldKeys, "auth.changed", "new"))
+	rotated := write("rotated.yaml", encrypt(newKeys, "auth.same", "s"), encrypt(newKeys, "auth.changed", "new"))
+
+	tests := []struct {
+		name             string
+		b                string
+		oldKeys, newKeys *sealed.Keyring
+		want             map[string]bool // path to NotCompared
+	}{
+		{"re-encrypted", reencrypted, oldKeys, oldKeys, map[string]bool{"auth.changed": false}},
+		{"rotated", rotated, oldKeys, newKeys, map[string]bool{"auth.changed": false}},
+		{"without keys", reencrypted, nil, nil, map[string]bool{"auth.same": true, "auth.changed": true}},
+		{"without the new key", rotated, oldKeys, oldKeys, map[string]bool{"auth.same": true, "auth.changed": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]bool)
+			for _, c := range diffFiles(a, tt.b, nil, tt.oldKeys, tt.newKeys) {
+				got[c.Path] = c.NotCompared
+				if c.Old == nil || c.New == nil || *c.Old != redacted || *c.New != redacted {
+					t.Errorf("%s: values are not redacted", c.Path)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("changes = %v, want %v", got, tt.want)
+			}
+			for path, notCompared := range tt.want {
+				if g, ok := got[path]; !ok || g != notCompared {
+					t.Errorf("changes = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
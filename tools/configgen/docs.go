@@ -0,0 +1,247 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// docSection is a top-level section of the reference, such as game or web.
+type docSection struct {
+	Key         string
+	Description string
+	Fields      []docField
+}
+
+// docField documents one leaf setting.
+type docField struct {
+	Path        string
+	GoField     string
+	Type        string
+	Default     string
+	EnvVars     []string
+	Required    string
+	Secret      bool
+	Reload      string
+	Enum        []string
+	Description string
+}
+
+func docsCommand(args []string) {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	outDir := fs.String("out", "docs", "directory to write config.md and config.html to")
+	fs.Parse(args)
+
+	sections := buildDocs(readTemplateTree())
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		panic(err)
+	}
+	writeMarkdownDocs(filepath.Join(*outDir, "config.md"), sections)
+	writeHTMLDocs(filepath.Join(*outDir, "config.html"), sections)
+
+	fmt.Printf("📚 Reference written to %s\n", *outDir)
+}
+
+// buildDocs groups the documented leaves by top-level section, in template
+// order.
+func buildDocs(root *YamlNode) []docSection {
+	meta := make(map[string]FieldMeta)
+	for _, f := range collectFieldMeta(root) {
+		meta[f.Path] = f
+	}
+
+	var sections []docSection
+	for _, child := range root.Children {
+		if shouldSkipField(child) || len(child.Children) == 0 {
+			continue
+		}
+
+		section := docSection{Key: child.Key, Description: child.Description}
+
+		var collect func(node *YamlNode)
+		collect = func(node *YamlNode) {
+			if len(node.Children) > 0 {
+				for _, c := range node.Children {
+					collect(c)
+				}
+				return
+			}
+			section.Fields = append(section.Fields, newDocField(node, meta[node.Path]))
+		}
+		collect(child)
+
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+func newDocField(node *YamlNode, meta FieldMeta) docField {
+	field := docField{
+		Path:        node.Path,
+		GoField:     "Config." + meta.GoPath,
+		Type:        meta.GoType,
+		Default:     leafDefault(node),
+		EnvVars:     uniqueStrings(node.EnvVars),
+		Required:    "no",
+		Secret:      node.Secret,
+		Reload:      node.Reload,
+		Enum:        node.Enum,
+		Description: node.Description,
+	}
+
+	if node.Required {
+		field.Required = "yes"
+		if len(node.RequiredIf) > 0 {
+			field.Required = "if " + strings.Join(node.RequiredIf, " and ")
+		}
+	}
+	if node.Secret && field.Default != "" {
+		field.Default = redacted
+	}
+
+	return field
+}
+
+// leafDefault formats the default of a leaf for display. Sequences are
+// listed comma-separated.
+func leafDefault(node *YamlNode) string {
+	if items, ok := node.Value.([]interface{}); ok {
+		values := make([]string, len(items))
+		for i, item := range items {
+			values[i] = fmt.Sprint(item)
+		}
+		return strings.Join(values, ", ")
+	}
+	return node.Default
+}
+
+func writeMarkdownDocs(filename string, sections []docSection) {
+	var b strings.Builder
+	b.WriteString("<!-- Code generated by configgen. DO NOT EDIT. -->\n")
+	b.WriteString("# Configuration reference\n\n")
+	b.WriteString("Every setting in `config/config.yaml.template`, grouped by section.\n")
+
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.Key)
+		if section.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", section.Description)
+		}
+
+		b.WriteString("| Setting | Go field | Type | Default | Env var | Required | Reload | Description |\n")
+		b.WriteString("|---|---|---|---|---|---|---|---|\n")
+		for _, f := range section.Fields {
+			fmt.Fprintf(&b, "| `%s` | `%s` | `%s` | %s | %s | %s | %s | %s |\n",
+				f.Path,
+				f.GoField,
+				f.Type,
+				markdownCode(f.Default),
+				markdownCodeList(f.EnvVars),
+				f.Required,
+				f.Reload,
+				markdownEscape(fieldNotes(f)),
+			)
+		}
+	}
+
+	if err := os.WriteFile(filename, []byte(b.String()), 0o644); err != nil {
+		panic(err)
+	}
+}
+
+// fieldNotes combines the description with the secret marker and allowed
+// values.
+func fieldNotes(f docField) string {
+	var notes []string
+	if f.Secret {
+		notes = append(notes, "🔒 Secret.")
+	}
+	if f.Description != "" {
+		notes = append(notes, f.Description)
+	}
+	if len(f.Enum) > 0 {
+		notes = append(notes, "One of: "+strings.Join(f.Enum, ", ")+".")
+	}
+	return strings.Join(notes, " ")
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+func markdownCode(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return "`" + markdownEscape(s) + "`"
+}
+
+func markdownCodeList(values []string) string {
+	if len(values) == 0 {
+		return "—"
+	}
+	codes := make([]string, len(values))
+	for i, v := range values {
+		codes[i] = markdownCode(v)
+	}
+	return strings.Join(codes, ", ")
+}
+
+const htmlDocsTemplate = `<!DOCTYPE html>
+<!-- Code generated by configgen. DO NOT EDIT. -->
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Configuration reference</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2rem; font-size: 0.9rem; }
+th, td { border: 1px solid #ddd; padding: 0.4rem 0.6rem; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+code { background: #f6f8fa; padding: 0 0.2rem; }
+.secret { color: #b00; }
+nav a { margin-right: 1rem; }
+</style>
+</head>
+<body>
+<h1>Configuration reference</h1>
+<p>Every setting in <code>config/config.yaml.template</code>, grouped by section.</p>
+<nav>{{range .}}<a href="#{{.Key}}">{{.Key}}</a>{{end}}</nav>
+{{range .}}
+<h2 id="{{.Key}}">{{.Key}}</h2>
+{{if .Description}}<p>{{.Description}}</p>
+{{end}}<table>
+<tr><th>Setting</th><th>Go field</th><th>Type</th><th>Default</th><th>Env var</th><th>Required</th><th>Reload</th><th>Description</th></tr>
+{{range .Fields}}<tr>
+<td><code>{{.Path}}</code></td>
+<td><code>{{.GoField}}</code></td>
+<td><code>{{.Type}}</code></td>
+<td>{{if .Default}}<code>{{.Default}}</code>{{else}}—{{end}}</td>
+<td>{{range $i, $v := .EnvVars}}{{if $i}}, {{end}}<code>{{$v}}</code>{{else}}—{{end}}</td>
+<td>{{.Required}}</td>
+<td>{{.Reload}}</td>
+<td>{{if .Secret}}<span class="secret">🔒 Secret.</span> {{end}}{{.Description}}{{if .Enum}} One of: {{range $i, $v := .Enum}}{{if $i}}, {{end}}<code>{{$v}}</code>{{end}}.{{end}}</td>
+</tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`
+
+func writeHTMLDocs(filename string, sections []docSection) {
+	t := template.Must(template.New("docs").Parse(htmlDocsTemplate))
+
+	file, err := os.Create(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	if err := t.Execute(file, sections); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"project/internal/placeholder"
+	"project/internal/sealed"
+	"project/internal/yamltree"
+)
+
+// pathList collects repeated --path flags.
+type pathList []string
+
+func (p *pathList) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *pathList) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+// scalarEdit replaces the source text of one scalar node.
+type scalarEdit struct {
+	node *yaml.Node
+	text string
+}
+
+func keygenCommand(args []string) {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	useAge := fs.Bool("age", false, "generate an age identity instead of an AES key")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: configgen keygen [--age] <key-file>")
+		os.Exit(1)
+	}
+
+	generate := sealed.GenerateKey
+	if *useAge {
+		generate = sealed.GenerateAgeIdentity
+	}
+
+	key, err := generate()
+	if err != nil {
+		panic(err)
+	}
+
+	file, err := os.OpenFile(fs.Arg(0), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(key); err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("🔑 Key written to %s\n", fs.Arg(0))
+}
+
+// encryptCommand encrypts leaves of a config file in place. Without --path
+// it targets every secret leaf holding a literal value; leaves that are
+// placeholders are left alone, since their values live elsewhere.
+func encryptCommand(args []string) {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	keyFile := fs.String("key", os.Getenv("CONFIG_KEY_FILE"), "AES key file or age identity file")
+	var paths pathList
+	fs.Var(&paths, "path", "YAML path to encrypt (repeatable, defaults to secret fields)")
+	fs.Parse(args)
+
+	filename, keyring := cryptArgs(fs, *keyFile, "encrypt")
+
+	count := rewriteLeaves(filename, func(e *yamltree.Entry) (string, bool) {
+		value := e.Value.Value
+		if sealed.IsEncrypted(value) {
+			return "", false
+		}
+
+		if len(paths) > 0 {
+			if !slices.Contains(paths, e.Path) {
+				return "", false
+			}
+		} else {
+			t, err := placeholder.Parse(value)
+			if err != nil || len(t.Refs()) > 0 {
+				return "", false
+			}
+			if !isSecret(e, nil) {
+				return "", false
+			}
+		}
+
+		encrypted, err := keyring.Encrypt(e.Path, value, scalarType(e.Value))
+		if err != nil {
+			panic(fmt.Sprintf("Failed to encrypt %s: %v", e.Path, err))
+		}
+		return encrypted, true
+	})
+
+	fmt.Printf("🔒 Encrypted %d value(s) in %s\n", count, filename)
+}
+
+func decryptCommand(args []string) {
+	fs := flag.NewFlagSet("decrypt", flag.ExitOnError)
+	keyFile := fs.String("key", os.Getenv("CONFIG_KEY_FILE"), "AES key file or age identity file")
+	var paths pathList
+	fs.Var(&paths, "path", "YAML path to decrypt (repeatable, defaults to all encrypted values)")
+	fs.Parse(args)
+
+	filename, keyring := cryptArgs(fs, *keyFile, "decrypt")
+
+	count := rewriteLeaves(filename, func(e *yamltree.Entry) (string, bool) {
+		if !sealed.IsEncrypted(e.Value.Value) {
+			return "", false
+		}
+		if len(paths) > 0 && !slices.Contains(paths, e.Path) {
+			return "", false
+		}
+
+		value, typ, err := keyring.Decrypt(e.Path, e.Value.Value)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to decrypt %s: %v", e.Path, err))
+		}
+		if typ == "str" {
+			return strconv.Quote(value), true
+		}
+		return value, true
+	})
+
+	fmt.Printf("🔓 Decrypted %d value(s) in %s\n", count, filename)
+}
+
+func rotateKeyCommand(args []string) {
+	fs := flag.NewFlagSet("rotate-key", flag.ExitOnError)
+	keyFile := fs.String("key", os.Getenv("CONFIG_KEY_FILE"), "current AES key file or age identity file")
+	newKeyFile := fs.String("new-key", "", "new AES key file or age identity file")
+	fs.Parse(args)
+
+	if *newKeyFile == "" {
+		fmt.Println("Usage: configgen rotate-key --key <old> --new-key <new> <config-file>")
+		os.Exit(1)
+	}
+
+	filename, oldKeyring := cryptArgs(fs, *keyFile, "rotate-key")
+	newKeyring, err := sealed.ReadKeyFile(*newKeyFile)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	count := rewriteLeaves(filename, func(e *yamltree.Entry) (string, bool) {
+		if !sealed.IsEncrypted(e.Value.Value) {
+			return "", false
+		}
+
+		value, typ, err := oldKeyring.Decrypt(e.Path, e.Value.Value)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to decrypt %s: %v", e.Path, err))
+		}
+		encrypted, err := newKeyring.Encrypt(e.Path, value, typ)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to encrypt %s: %v", e.Path, err))
+		}
+		return encrypted, true
+	})
+
+	fmt.Printf("🔑 Re-encrypted %d value(s) in %s\n", count, filename)
+}
+
+func cryptArgs(fs *flag.FlagSet, keyFile, command string) (string, *sealed.Keyring) {
+	if fs.NArg() != 1 || keyFile == "" {
+		fmt.Printf("Usage: configgen %s --key <key-file> [--path yaml.path]... <config-file>\n", command)
+		os.Exit(1)
+	}
+
+	keyring, err := sealed.ReadKeyFile(keyFile)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	return fs.Arg(0), keyring
+}
+
+// rewriteLeaves rewrites the scalar leaves of filename for which replace
+// returns true, leaving comments, ordering and formatting untouched. It
+// returns the number of rewritten leaves.
+func rewriteLeaves(filename string, replace func(e *yamltree.Entry) (string, bool)) int {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to read %s: %v", filename, err))
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		panic(fmt.Sprintf("Failed to parse YAML: %v", err))
+	}
+
+	var edits []scalarEdit
+	yamltree.Walk(&root, func(e *yamltree.Entry) {
+		if e.Key == nil || !e.IsLeaf() {
+			return
+		}
+		if text, ok := replace(e); ok {
+			edits = append(edits, scalarEdit{node: e.Value, text: text})
+		}
+	})
+
+	if len(edits) == 0 {
+		return 0
+	}
+
+	rewritten, err := rewriteScalars(content, edits)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to rewrite %s: %v", filename, err))
+	}
+
+	if err := os.WriteFile(filename, rewritten, 0o644); err != nil {
+		panic(err)
+	}
+
+	return len(edits)
+}
+
+// rewriteScalars splices each edit into content at the position of its
+// node. Only single-line scalars can be rewritten.
+func rewriteScalars(content []byte, edits []scalarEdit) ([]byte, error) {
+	lines := strings.SplitAfter(string(content), "\n")
+
+	// Apply edits right to left so earlier columns stay valid.
+	slices.SortFunc(edits, func(a, b scalarEdit) int {
+		if a.node.Line != b.node.Line {
+			return a.node.Line - b.node.Line
+		}
+		return b.node.Column - a.node.Column
+	})
+
+	for _, edit := range edits {
+		n := edit.node
+		if n.Line < 1 || n.Line > len(lines) {
+			return nil, fmt.Errorf("line %d out of range", n.Line)
+		}
+
+		line := lines[n.Line-1]
+		start := runeOffset(line, n.Column-1)
+		end, err := scalarEnd(line, start, n)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n.Line, err)
+		}
+
+		lines[n.Line-1] = line[:start] + edit.text + line[end:]
+	}
+
+	return []byte(strings.Join(lines, "")), nil
+}
+
+func runeOffset(s string, runes int) int {
+	offset := 0
+	for i := 0; i < runes && offset < len(s); i++ {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// scalarEnd returns the byte offset just past the scalar token starting at
+// start, checking that the token really holds the node's value.
+func scalarEnd(line string, start int, n *yaml.Node) (int, error) {
+	end := -1
+
+	switch n.Style {
+	case yaml.DoubleQuotedStyle:
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\\' {
+				i++
+			} else if line[i] == '"' {
+				end = i + 1
+				break
+			}
+		}
+	case yaml.SingleQuotedStyle:
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				end = i + 1
+				break
+			}
+		}
+	case 0:
+		end = len(strings.TrimRight(line, "\r\n"))
+		if i := strings.Index(line[start:], " #"); i >= 0 {
+			end = start + i
+		}
+		end = start + len(strings.TrimRight(line[start:end], " \t"))
+	default:
+		return 0, fmt.Errorf("multi-line scalars are not supported")
+	}
+
+	if end < 0 {
+		return 0, fmt.Errorf("unterminated scalar")
+	}
+
+	var value string
+	if err := yaml.Unmarshal([]byte(line[start:end]), &value); err != nil || value != n.Value {
+		return 0, fmt.Errorf("cannot locate the value of this scalar")
+	}
+
+	return end, nil
+}
+
+// scalarType returns the YAML type name recorded alongside encrypted values.
+func scalarType(n *yaml.Node) string {
+	switch n.ShortTag() {
+	case "!!int":
+		return "int"
+	case "!!float":
+		return "float"
+	case "!!bool":
+		return "bool"
+	default:
+		return "str"
+	}
+}
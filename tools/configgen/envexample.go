@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"project/internal/placeholder"
+)
+
+// envVarDoc describes one environment variable in a .env example file.
+type envVarDoc struct {
+	Name string
+	// Section is the top-level YAML section the variable feeds.
+	Section string
+	// File and Line locate the first leaf that references the variable.
+	File        string
+	Line        int
+	Path        string
+	Description string
+	Default     string
+	Example     string
+	Enum        []string
+	Secret      bool
+	Required    bool
+	RequiredIf  []string
+	// Type is the Go type of the field the variable feeds, or string when
+	// the variable is only part of the field's value.
+	Type string
+	// Usage is the leaf's value with the other placeholders elided, set
+	// when the variable is only part of it, as in a connection string.
+	Usage string
+}
+
+// collectEnvVarDocs lists the variables referenced by the leaves of root,
+// parsed from filename, in document order. A variable used by several
+// leaves is documented at its first use. Of a secret leaf built from several
+// variables, such as a connection string, only the variables named like
+// secrets are secret.
+func collectEnvVarDocs(root *YamlNode, filename string) []envVarDoc {
+	var docs []envVarDoc
+	seen := make(map[string]bool)
+
+	for _, section := range root.Children {
+		var collect func(node *YamlNode)
+		collect = func(node *YamlNode) {
+			for _, child := range node.Children {
+				collect(child)
+			}
+
+			raw, ok := node.Value.(string)
+			if !ok || len(node.EnvVars) == 0 {
+				return
+			}
+			t, err := placeholder.Parse(raw)
+			if err != nil {
+				panic(fmt.Sprintf("Failed to parse placeholders in %s: %v", node.Path, err))
+			}
+			whole := len(t) == 1 && t[0].Ref != nil
+
+			for _, ref := range t.Vars() {
+				if seen[ref.Name] {
+					continue
+				}
+				seen[ref.Name] = true
+
+				doc := envVarDoc{
+					Name:        ref.Name,
+					Section:     section.Key,
+					File:        filename,
+					Line:        node.Line,
+					Path:        node.Path,
+					Description: node.Description,
+					Type:        "string",
+					Default:     ref.Default(),
+					Example:     node.Example,
+					Secret:      isSecretName(ref.Name) || (whole && node.Secret),
+					Required:    node.Required && ref.Required(),
+				}
+				if doc.Required {
+					doc.RequiredIf = node.RequiredIf
+				}
+				if whole {
+					doc.Type = leafGoType(node)
+					doc.Enum = node.Enum
+				} else {
+					doc.Usage = placeholderUsage(t, ref.Name)
+				}
+				if doc.Secret || doc.Type == "Secret" {
+					doc.Type = "secret"
+				}
+				docs = append(docs, doc)
+			}
+		}
+		collect(section)
+	}
+
+	return docs
+}
+
+// placeholderUsage renders t with the placeholders of variables other than
+// name elided, showing the part name plays in the value.
+func placeholderUsage(t placeholder.Template, name string) string {
+	var b strings.Builder
+	for _, part := range t {
+		if part.Ref == nil {
+			b.WriteString(part.Literal)
+			continue
+		}
+
+		uses := false
+		for _, ref := range append([]*placeholder.Ref{part.Ref}, part.Ref.Word.Refs()...) {
+			uses = uses || ref.Name == name
+		}
+		if uses {
+			b.WriteString("${" + name + "}")
+		} else {
+			b.WriteString("…")
+		}
+	}
+	return b.String()
+}
+
+// mergeEnvVarDocs applies the variables referenced by a profile overlay to
+// the template's. An overlay placeholder replaces the default and
+// requirement of a variable it shares with the template; new variables are
+// added at the end of their section.
+func mergeEnvVarDocs(base, overlay []envVarDoc) []envVarDoc {
+	merged := append([]envVarDoc(nil), base...)
+
+	for _, doc := range overlay {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name != doc.Name {
+				continue
+			}
+			merged[i].Default = doc.Default
+			merged[i].Type = doc.Type
+			merged[i].Required = doc.Required
+			merged[i].RequiredIf = doc.RequiredIf
+			merged[i].File = doc.File
+			merged[i].Line = doc.Line
+			if doc.Description != "" {
+				merged[i].Description = doc.Description
+			}
+			replaced = true
+			break
+		}
+		if replaced {
+			continue
+		}
+
+		// Keep the new variable with the rest of its section.
+		at := len(merged)
+		for i := range merged {
+			if merged[i].Section == doc.Section {
+				at = i + 1
+			}
+		}
+		merged = slices.Insert(merged, at, doc)
+	}
+
+	return merged
+}
+
+// overlayEnvVarDocs documents the variables referenced by a profile overlay,
+// or returns nil when the overlay does not exist.
+func overlayEnvVarDocs(profile string) []envVarDoc {
+	overlay := readOverlayTree(profile)
+	if overlay == nil {
+		return nil
+	}
+	return collectEnvVarDocs(overlay, profileOverlayPath(profile))
+}
+
+// writeEnvExample writes the documented variables grouped by section. Each
+// entry carries the description, the YAML path and type, and markers for
+// required, enum and example values. Secrets are always written empty.
+func writeEnvExample(filename string, docs []envVarDoc) {
+	var b strings.Builder
+	b.WriteString("# Generated environment variables\n")
+	b.WriteString("# Copy this file to .env.local and fill in your values\n")
+
+	section := ""
+	for _, doc := range docs {
+		if doc.Section != section {
+			section = doc.Section
+			fmt.Fprintf(&b, "\n# ===== %s =====\n", section)
+		}
+
+		b.WriteString("\n")
+		if doc.Description != "" {
+			fmt.Fprintf(&b, "# %s\n", doc.Description)
+		}
+		if doc.Usage != "" {
+			fmt.Fprintf(&b, "# Part of %s: %s (%s)\n", doc.Path, doc.Usage, doc.Type)
+		} else {
+			fmt.Fprintf(&b, "# %s (%s)\n", doc.Path, doc.Type)
+		}
+		if doc.Required {
+			if len(doc.RequiredIf) > 0 {
+				fmt.Fprintf(&b, "# REQUIRED if %s\n", strings.Join(doc.RequiredIf, " and "))
+			} else {
+				b.WriteString("# REQUIRED\n")
+			}
+		}
+		if len(doc.Enum) > 0 {
+			fmt.Fprintf(&b, "# One of: %s\n", strings.Join(doc.Enum, ", "))
+		}
+		if doc.Example != "" {
+			fmt.Fprintf(&b, "# Example: %s\n", doc.Example)
+		}
+
+		value := doc.Default
+		if doc.Secret || doc.Required {
+			value = ""
+		}
+		fmt.Fprintf(&b, "%s=%s\n", doc.Name, value)
+	}
+
+	if err := os.WriteFile(filename, []byte(b.String()), 0o644); err != nil {
+		panic(err)
+	}
+}
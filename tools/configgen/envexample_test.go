@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestCollectEnvVarDocs(t *testing.T) {
+	const template = `
+web:
+  port: "${SERVER_PORT:8080}"
+  workers: 4
+database:
+  connection: "postgresql://${DB_USER}:${DB_PASSWORD}@${DB_HOST:-localhost}/app" # @secret
+`
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(template), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	docs := make(map[string]envVarDoc)
+	for _, doc := range collectEnvVarDocs(buildConfigTree(&root), "test.yaml") {
+		docs[doc.Name] = doc
+	}
+
+	tests := []struct {
+		name, typ, usage string
+	}{
+		// The type is the generated field's, not the default's.
+		{"SERVER_PORT", "string", ""},
+		{"DB_USER", "string", "postgresql://${DB_USER}:…@…/app"},
+		{"DB_PASSWORD", "secret", "postgresql://…:${DB_PASSWORD}@…/app"},
+		{"DB_HOST", "string", "postgresql://…:…@${DB_HOST}/app"},
+	}
+	for _, tt := range tests {
+		doc, ok := docs[tt.name]
+		if !ok {
+			t.Errorf("%s is not documented", tt.name)
+			continue
+		}
+		if doc.Type != tt.typ || doc.Usage != tt.usage {
+			t.Errorf("%s: type %q, usage %q; want %q, %q", tt.name, doc.Type, doc.Usage, tt.typ, tt.usage)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"project/internal/environ"
+)
+
+// removedMarker prefixes variables that env sync commented out because the
+// template no longer references them.
+const removedMarker = "# removed: "
+
+// loaderVars are read by the config loader itself and are never reported as
+// removed.
+var loaderVars = []string{"APP_ENV", "CONFIG_KEY_FILE"}
+
+func envCommand(args []string) {
+	if len(args) < 1 || args[0] != "sync" {
+		fmt.Println("Usage: configgen env sync [--dry-run] [--file .env.local] [--profile name]")
+		os.Exit(1)
+	}
+
+	fs := flag.NewFlagSet("env sync", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "print the changes without writing the file")
+	filename := fs.String("file", ".env.local", "dotenv file to sync")
+	profile := fs.String("profile", os.Getenv("APP_ENV"), "also sync variables added by config.<profile>.yaml")
+	fs.Parse(args[1:])
+
+	root := readTemplateTree()
+	docs := collectEnvVarDocs(root, templatePath)
+	if *profile != "" {
+		docs = mergeEnvVarDocs(docs, overlayEnvVarDocs(*profile))
+	}
+
+	paths := make(map[string]bool)
+	for _, f := range collectFieldMeta(root) {
+		paths[f.Path] = true
+	}
+
+	content, err := os.ReadFile(*filename)
+	if err != nil && !os.IsNotExist(err) {
+		panic(err)
+	}
+
+	before := splitLines(string(content))
+	after := syncEnvLines(before, docs, paths)
+
+	if *dryRun {
+		printLineDiff(before, after)
+		return
+	}
+
+	if strings.Join(before, "\n") == strings.Join(after, "\n") {
+		fmt.Printf("✅ %s is up to date\n", *filename)
+		return
+	}
+
+	if err := os.WriteFile(*filename, []byte(strings.Join(after, "\n")+"\n"), 0o644); err != nil {
+		panic(err)
+	}
+	fmt.Printf("✅ Synced %s\n", *filename)
+}
+
+// syncEnvLines brings a dotenv file in line with the template. Lines are
+// kept in place with their values and comments; variables the template no
+// longer uses are commented out with removedMarker, variables that come back
+// are restored, and new ones are appended with their defaults.
+func syncEnvLines(lines []string, docs []envVarDoc, paths map[string]bool) []string {
+	known := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		known[doc.Name] = true
+	}
+
+	defined := make(map[string]bool)
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if key, _, ok := environ.ParseLine(line); ok {
+			defined[key] = true
+			if !envVarUsed(key, known, paths) {
+				line = removedMarker + strings.TrimSpace(line)
+			}
+		} else if rest, ok := strings.CutPrefix(strings.TrimSpace(line), removedMarker); ok {
+			if key, _, ok := environ.ParseLine(rest); ok && known[key] && !defined[key] {
+				defined[key] = true
+				line = rest
+			}
+		}
+		result = append(result, line)
+	}
+
+	var added []string
+	for _, doc := range docs {
+		if defined[doc.Name] || defined[doc.Name+"_FILE"] {
+			continue
+		}
+		defined[doc.Name] = true
+
+		value := doc.Default
+		if doc.Secret || doc.Required {
+			value = ""
+		}
+		if doc.Description != "" {
+			added = append(added, "# "+doc.Description)
+		}
+		added = append(added, fmt.Sprintf("%s=%s", doc.Name, value))
+	}
+
+	if len(added) > 0 {
+		if len(result) > 0 && result[len(result)-1] != "" {
+			result = append(result, "")
+		}
+		result = append(result, "# Added by configgen env sync")
+		result = append(result, added...)
+	}
+
+	return result
+}
+
+// envVarUsed reports whether the loader reads name: through a placeholder
+// in known, as NAME_FILE, as a direct override of one of the config paths
+// such as GAME_NAME, or for its own settings.
+func envVarUsed(name string, known, paths map[string]bool) bool {
+	return known[name] || known[strings.TrimSuffix(name, "_FILE")] || paths[envKey(name)] ||
+		slices.Contains(loaderVars, name)
+}
+
+// envKey mirrors the loader's mapping of variables onto config keys.
+func envKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", ".")
+}
+
+func splitLines(content string) []string {
+	content = strings.TrimSuffix(content, "\n")
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
+
+// printLineDiff prints the lines that differ between before and after. Sync
+// only rewrites lines in place and appends new ones, so lines are compared
+// position by position.
+func printLineDiff(before, after []string) {
+	changed := false
+	for i, line := range after {
+		switch {
+		case i >= len(before):
+			fmt.Printf("+ %s\n", line)
+		case before[i] != line:
+			fmt.Printf("- %s\n+ %s\n", before[i], line)
+		default:
+			continue
+		}
+		changed = true
+	}
+
+	if !changed {
+		fmt.Println("✅ No changes")
+	}
+}
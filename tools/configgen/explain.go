@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"project/config"
+)
+
+// explainCommand prints every setting of the effective configuration with
+// the source that set it. Settings whose required variables are unset are
+// listed as missing rather than failing the command.
+func explainCommand(args []string) {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	env := addEnvFlags(fs, "environment profile to load")
+	keyFile := fs.String("key", "", "AES key file or age identity file for encrypted values")
+	format := fs.String("format", "text", "output format: text or json")
+	fs.Parse(args)
+
+	opts := env.options()
+	if *keyFile != "" {
+		opts = append(opts, config.WithKeyFile(*keyFile))
+	}
+
+	settings, err := config.Explain(opts...)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	switch *format {
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+		for _, s := range settings {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", s.Path, explainValue(s), s.Source)
+		}
+		w.Flush()
+	case "json":
+		out, err := json.MarshalIndent(settings, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(string(out))
+	default:
+		fmt.Printf("Unknown format: %s\n", *format)
+		os.Exit(1)
+	}
+}
+
+// explainValue returns the value column for s, naming the missing variables
+// of a setting that cannot be resolved.
+func explainValue(s config.Setting) string {
+	if len(s.Missing) == 0 {
+		return s.Value
+	}
+
+	var names []string
+	for _, v := range s.Missing {
+		names = append(names, v.Name)
+	}
+	return "missing: " + strings.Join(names, ", ")
+}
+
+// envFlags are the flags that select the environment the loader resolves
+// variables from, shared by the commands that resolve them.
+type envFlags struct {
+	profile     *string
+	precedence  *string
+	secretsDirs pathList
+}
+
+func addEnvFlags(fs *flag.FlagSet, profileUsage string) *envFlags {
+	f := &envFlags{
+		profile:    fs.String("profile", os.Getenv("APP_ENV"), profileUsage),
+		precedence: fs.String("precedence", "process", "layer that wins for variables set in both: process or dotenv"),
+	}
+	fs.Var(&f.secretsDirs, "secrets-dir", "directory of secret files named after variables (repeatable)")
+	return f
+}
+
+// options returns the loader options the flags select.
+func (f *envFlags) options() []config.Option {
+	opts := []config.Option{config.WithProfile(*f.profile)}
+	switch *f.precedence {
+	case "process":
+	case "dotenv":
+		opts = append(opts, config.WithPrecedence(config.DotenvFirst))
+	default:
+		fmt.Printf("Unknown precedence: %s\n", *f.precedence)
+		os.Exit(1)
+	}
+	for _, dir := range f.secretsDirs {
+		opts = append(opts, config.WithSecretsDir(dir))
+	}
+	return opts
+}
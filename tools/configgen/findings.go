@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+)
+
+// Rule IDs of validation findings.
+const (
+	ruleMissingEnv   = "missing-env"
+	ruleExtraEnv     = "extra-env"
+	ruleTypeMismatch = "type-mismatch"
+	ruleUnknownKey   = "unknown-key"
+)
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// ruleInfo documents a rule in SARIF output.
+type ruleInfo struct {
+	ID          string
+	Description string
+}
+
+var validateRules = []ruleInfo{
+	{ruleMissingEnv, "A variable the template requires is not set or is empty."},
+	{ruleExtraEnv, "A variable is set but not used by the template."},
+	{ruleTypeMismatch, "A variable's value does not parse as its type or is not an allowed value."},
+	{ruleUnknownKey, "A config file sets a key the template does not define."},
+}
+
+// finding is one problem reported by validate or lint.
+type finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	// Path is the YAML path the finding concerns, if any.
+	Path string `json:"path,omitempty"`
+	Var  string `json:"var,omitempty"`
+}
+
+func (f finding) location() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// exitCode returns 1 when findings contain errors, or warnings and strict is
+// set, and 0 otherwise.
+func exitCode(findings []finding, strict bool) int {
+	for _, f := range findings {
+		if f.Severity == severityError || strict {
+			return 1
+		}
+	}
+	return 0
+}
+
+// printFindings writes findings in format. title names the check in text
+// and JUnit output, e.g. "Configuration validation".
+func printFindings(format, title string, rules []ruleInfo, findings []finding, strict bool) {
+	switch format {
+	case "text":
+		printFindingsText(title, findings, strict)
+	case "json":
+		printFindingsJSON(findings)
+	case "sarif":
+		printFindingsSARIF(rules, findings)
+	case "junit":
+		printFindingsJUnit(title, findings, strict)
+	default:
+		fmt.Printf("Unknown format: %s\n", format)
+		os.Exit(2)
+	}
+}
+
+func printFindingsText(title string, findings []finding, strict bool) {
+	errors, warnings := 0, 0
+	for _, f := range findings {
+		location := f.location()
+		if location != "" {
+			location = " (" + location + ")"
+		}
+
+		if f.Severity == severityError {
+			errors++
+			fmt.Printf("❌ %s%s\n", f.Message, location)
+		} else {
+			warnings++
+			fmt.Printf("⚠️  %s%s\n", f.Message, location)
+		}
+	}
+
+	switch {
+	case errors > 0:
+		fmt.Printf("❌ %s failed: %d error(s), %d warning(s)\n", title, errors, warnings)
+	case warnings > 0 && strict:
+		fmt.Printf("❌ %s failed: %d warning(s) in strict mode\n", title, warnings)
+	case warnings > 0:
+		fmt.Printf("✅ %s passed with %d warning(s)\n", title, warnings)
+	default:
+		fmt.Printf("✅ %s passed!\n", title)
+	}
+}
+
+func printFindingsJSON(findings []finding) {
+	if findings == nil {
+		findings = []finding{}
+	}
+	out, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(out))
+}
+
+// printFindingsSARIF writes a SARIF 2.1.0 log with one result per finding.
+func printFindingsSARIF(ruleInfos []ruleInfo, findings []finding) {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+	}
+	type region struct {
+		StartLine int `json:"startLine"`
+	}
+	type physicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *region `json:"region,omitempty"`
+	}
+	type logicalLocation struct {
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+	}
+	type location struct {
+		PhysicalLocation *physicalLocation `json:"physicalLocation,omitempty"`
+		LogicalLocations []logicalLocation `json:"logicalLocations,omitempty"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations,omitempty"`
+	}
+
+	var rules []rule
+	for _, r := range ruleInfos {
+		rules = append(rules, rule{ID: r.ID, ShortDescription: message{r.Description}})
+	}
+
+	results := []result{}
+	for _, f := range findings {
+		res := result{RuleID: f.Rule, Level: f.Severity, Message: message{f.Message}}
+
+		var loc location
+		if f.File != "" {
+			loc.PhysicalLocation = &physicalLocation{}
+			loc.PhysicalLocation.ArtifactLocation.URI = f.File
+			if f.Line > 0 {
+				loc.PhysicalLocation.Region = &region{StartLine: f.Line}
+			}
+		}
+		if f.Path != "" {
+			loc.LogicalLocations = []logicalLocation{{FullyQualifiedName: f.Path}}
+		}
+		if loc.PhysicalLocation != nil || loc.LogicalLocations != nil {
+			res.Locations = []location{loc}
+		}
+
+		results = append(results, res)
+	}
+
+	log := map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []interface{}{
+			map[string]interface{}{
+				"tool": map[string]interface{}{
+					"driver": map[string]interface{}{
+						"name":  "configgen",
+						"rules": rules,
+					},
+				},
+				"results": results,
+			},
+		},
+	}
+
+	out, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(out))
+}
+
+// printFindingsJUnit writes one test case per finding. Errors, and warnings
+// in strict mode, are failures; other warnings pass with the message as
+// output.
+func printFindingsJUnit(title string, findings []finding, strict bool) {
+	type failure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+	type testCase struct {
+		Name      string   `xml:"name,attr"`
+		ClassName string   `xml:"classname,attr"`
+		Failure   *failure `xml:"failure,omitempty"`
+		SystemOut string   `xml:"system-out,omitempty"`
+	}
+	type testSuite struct {
+		XMLName   xml.Name   `xml:"testsuite"`
+		Name      string     `xml:"name,attr"`
+		Tests     int        `xml:"tests,attr"`
+		Failures  int        `xml:"failures,attr"`
+		TestCases []testCase `xml:"testcase"`
+	}
+
+	suite := testSuite{Name: title, Tests: len(findings)}
+	for _, f := range findings {
+		subject := f.Var
+		if subject == "" {
+			subject = f.Path
+		}
+
+		tc := testCase{Name: f.Rule + ": " + subject, ClassName: f.Rule}
+		text := f.Message
+		if location := f.location(); location != "" {
+			text += " at " + location
+		}
+
+		if f.Severity == severityError || strict {
+			suite.Failures++
+			tc.Failure = &failure{Message: f.Message, Type: f.Severity, Text: text}
+		} else {
+			tc.SystemOut = text
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	out, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(xml.Header + string(out))
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"project/internal/placeholder"
+	"project/internal/sealed"
+	"project/internal/yamltree"
+)
+
+// ignoreMarker suppresses lint rules for a key and everything below it, as
+// in "# configgen:ignore snake-case-key". Without rule IDs it suppresses all
+// rules.
+const ignoreMarker = "configgen:ignore"
+
+var snakeCasePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// lintIssue is a problem a rule found at one entry. Subject is what the
+// rule's allow list is matched against, besides the entry's path.
+type lintIssue struct {
+	entry   *yamltree.Entry
+	subject string
+	message string
+}
+
+// lintRule is a check over the parsed template.
+type lintRule struct {
+	ruleInfo
+	severity string
+	check    func(entries []*yamltree.Entry) []lintIssue
+}
+
+// lintRules are all available rules, in the order they run.
+var lintRules = []lintRule{
+	{ruleInfo{"placeholder-default", "Placeholders should have a default unless allow-listed or @optional."}, severityWarning, lintPlaceholderDefaults},
+	{ruleInfo{"secret-default", "Secrets must not have literal values or non-empty defaults."}, severityError, lintSecretDefaults},
+	{ruleInfo{"env-type-conflict", "A variable must not feed settings of different types."}, severityError, lintEnvTypeConflicts},
+	{ruleInfo{"snake-case-key", "Keys should be snake_case."}, severityWarning, lintSnakeCaseKeys},
+	{ruleInfo{"field-name-collision", "Sibling keys must not map to the same Go field name."}, severityError, lintFieldNameCollisions},
+}
+
+func lintCommand(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	format := fs.String("format", "text", "output format: text, json, sarif or junit")
+	strict := fs.Bool("strict", false, "fail on warnings too")
+	listRules := fs.Bool("list-rules", false, "list the available rules and exit")
+	var only pathList
+	fs.Var(&only, "rule", "rule to run (repeatable, overrides .configgen.yaml)")
+	fs.Parse(args)
+
+	if *listRules {
+		for _, rule := range lintRules {
+			fmt.Printf("%-22s %-8s %s\n", rule.ID, rule.severity, rule.Description)
+		}
+		return
+	}
+
+	filename := templatePath
+	if fs.NArg() > 0 {
+		filename = fs.Arg(0)
+	}
+
+	settings := readSettings().Lint
+	if len(only) > 0 {
+		settings.Enable, settings.Disable = only, nil
+	}
+	rules, err := selectLintRules(settings)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(2)
+	}
+
+	findings := lintFile(filename, rules, settings.Rules)
+
+	var infos []ruleInfo
+	for _, rule := range rules {
+		infos = append(infos, rule.ruleInfo)
+	}
+	printFindings(*format, "Lint", infos, findings, *strict)
+	os.Exit(exitCode(findings, *strict))
+}
+
+// selectLintRules returns the rules enabled by settings.
+func selectLintRules(settings lintSettings) ([]lintRule, error) {
+	for _, id := range append(append([]string(nil), settings.Enable...), settings.Disable...) {
+		if !slices.ContainsFunc(lintRules, func(r lintRule) bool { return r.ID == id }) {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+	}
+	for id, opts := range settings.Rules {
+		if !slices.ContainsFunc(lintRules, func(r lintRule) bool { return r.ID == id }) {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+		switch opts.Severity {
+		case "", severityError, severityWarning:
+		default:
+			return nil, fmt.Errorf("invalid severity %q for lint rule %s; use %s or %s", opts.Severity, id, severityError, severityWarning)
+		}
+	}
+
+	var rules []lintRule
+	for _, rule := range lintRules {
+		if len(settings.Enable) > 0 && !slices.Contains(settings.Enable, rule.ID) {
+			continue
+		}
+		if slices.Contains(settings.Disable, rule.ID) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// lintFile runs rules over a template, dropping issues that are allow-listed
+// or suppressed by an ignore comment.
+func lintFile(filename string, rules []lintRule, options map[string]ruleOptions) []finding {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to read %s: %v", filename, err))
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		panic(fmt.Sprintf("Failed to parse %s: %v", filename, err))
+	}
+
+	var entries []*yamltree.Entry
+	yamltree.Walk(&root, func(e *yamltree.Entry) {
+		entries = append(entries, e)
+	})
+
+	var findings []finding
+	for _, rule := range rules {
+		opts := options[rule.ID]
+		severity := rule.severity
+		if opts.Severity != "" {
+			severity = opts.Severity
+		}
+
+		for _, issue := range rule.check(entries) {
+			if slices.Contains(opts.Allow, issue.subject) || slices.Contains(opts.Allow, issue.entry.Path) {
+				continue
+			}
+			if ignored(issue.entry, rule.ID) {
+				continue
+			}
+
+			line := issue.entry.Value.Line
+			if issue.entry.Key != nil {
+				line = issue.entry.Key.Line
+			}
+			findings = append(findings, finding{
+				Rule:     rule.ID,
+				Severity: severity,
+				Message:  issue.message,
+				File:     filename,
+				Line:     line,
+				Path:     issue.entry.Path,
+			})
+		}
+	}
+
+	return findings
+}
+
+// ignored reports whether an ignore comment on e or one of its parents
+// suppresses rule.
+func ignored(e *yamltree.Entry, rule string) bool {
+	for cur := e; cur != nil; cur = cur.Parent {
+		comments := []string{cur.Value.HeadComment, cur.Value.LineComment}
+		if cur.Key != nil {
+			comments = append(comments, cur.Key.HeadComment, cur.Key.LineComment)
+		}
+
+		for _, comment := range comments {
+			for _, line := range strings.Split(comment, "\n") {
+				_, rest, ok := strings.Cut(line, ignoreMarker)
+				if !ok {
+					continue
+				}
+				ids := strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+				if len(ids) == 0 || slices.Contains(ids, rule) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// scalarTemplate parses the placeholders of a scalar entry, or returns false
+// for other entries.
+func scalarTemplate(e *yamltree.Entry) (placeholder.Template, bool) {
+	if !e.IsLeaf() || e.Key == nil {
+		return nil, false
+	}
+	t, err := placeholder.Parse(e.Value.Value)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to parse placeholders in %s: %v", e.Path, err))
+	}
+	return t, true
+}
+
+func lintPlaceholderDefaults(entries []*yamltree.Entry) []lintIssue {
+	var issues []lintIssue
+	for _, e := range entries {
+		t, ok := scalarTemplate(e)
+		if !ok {
+			continue
+		}
+		if _, optional := e.Inherited("optional"); optional {
+			continue
+		}
+
+		for _, ref := range t.Vars() {
+			if ref.Op == placeholder.OpNone {
+				issues = append(issues, lintIssue{e, ref.Name, fmt.Sprintf("${%s} in %s has no default", ref.Name, e.Path)})
+			}
+		}
+	}
+	return issues
+}
+
+func lintSecretDefaults(entries []*yamltree.Entry) []lintIssue {
+	var issues []lintIssue
+	for _, e := range entries {
+		t, ok := scalarTemplate(e)
+		if !ok || sealed.IsEncrypted(e.Value.Value) {
+			continue
+		}
+
+		vars := t.Vars()
+		if len(t.Refs()) == 0 {
+			if e.Value.Value != "" && isSecret(e, nil) {
+				issues = append(issues, lintIssue{e, e.Key.Value, fmt.Sprintf("secret %s has a literal value; use a placeholder or encrypt it", e.Path)})
+			}
+			continue
+		}
+
+		whole := len(t) == 1 && t[0].Ref != nil
+		for _, ref := range vars {
+			secret := isSecretName(ref.Name) || (whole && isSecret(e, nil))
+			if secret && ref.Default() != "" {
+				issues = append(issues, lintIssue{e, ref.Name, fmt.Sprintf("secret ${%s} in %s has a default value", ref.Name, e.Path)})
+			}
+		}
+	}
+	return issues
+}
+
+func lintEnvTypeConflicts(entries []*yamltree.Entry) []lintIssue {
+	type binding struct {
+		goType string
+		entry  *yamltree.Entry
+	}
+
+	var issues []lintIssue
+	bound := make(map[string]binding)
+	for _, e := range entries {
+		t, ok := scalarTemplate(e)
+		if !ok {
+			continue
+		}
+
+		whole := len(t) == 1 && t[0].Ref != nil
+		for _, ref := range t.Vars() {
+			goType := "string"
+			if whole {
+				if !ref.HasDefault() {
+					continue
+				}
+				goType = inferGoType(ref.Default())
+			}
+
+			first, ok := bound[ref.Name]
+			if !ok {
+				bound[ref.Name] = binding{goType, e}
+				continue
+			}
+			if first.goType != goType {
+				issues = append(issues, lintIssue{e, ref.Name, fmt.Sprintf(
+					"%s is used as %s in %s but as %s in %s (line %d)",
+					ref.Name, goType, e.Path, first.goType, first.entry.Path, first.entry.Value.Line)})
+			}
+		}
+	}
+	return issues
+}
+
+func lintSnakeCaseKeys(entries []*yamltree.Entry) []lintIssue {
+	var issues []lintIssue
+	for _, e := range entries {
+		if e.Key != nil && !snakeCasePattern.MatchString(e.Key.Value) {
+			issues = append(issues, lintIssue{e, e.Key.Value, fmt.Sprintf("key %q at %s is not snake_case", e.Key.Value, e.Path)})
+		}
+	}
+	return issues
+}
+
+func lintFieldNameCollisions(entries []*yamltree.Entry) []lintIssue {
+	var issues []lintIssue
+	names := make(map[*yamltree.Entry]map[string]string)
+	for _, e := range entries {
+		if e.Key == nil {
+			continue
+		}
+		if names[e.Parent] == nil {
+			names[e.Parent] = make(map[string]string)
+		}
+
+		name := entryGoName(e)
+		if other, ok := names[e.Parent][name]; ok {
+			issues = append(issues, lintIssue{e, e.Key.Value, fmt.Sprintf("keys %q and %q both map to field %s", other, e.Key.Value, name)})
+			continue
+		}
+		names[e.Parent][name] = e.Key.Value
+	}
+	return issues
+}
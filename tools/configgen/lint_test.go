@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestSelectLintRulesSeverity(t *testing.T) {
+	tests := []struct {
+		severity string
+		ok       bool
+	}{
+		{"", true},
+		{"error", true},
+		{"warning", true},
+		{"fatal", false},
+		{"Error", false},
+	}
+
+	for _, tt := range tests {
+		settings := lintSettings{Rules: map[string]ruleOptions{"snake-case-key": {Severity: tt.severity}}}
+		if _, err := selectLintRules(settings); (err == nil) != tt.ok {
+			t.Errorf("severity %q: err = %v, want ok = %v", tt.severity, err, tt.ok)
+		}
+	}
+
+	settings := lintSettings{Rules: map[string]ruleOptions{"snake-case": {}}}
+	if _, err := selectLintRules(settings); err == nil {
+		t.Error("options for an unknown rule were accepted")
+	}
+}
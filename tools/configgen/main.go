@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"regexp"
@@ -10,6 +11,11 @@ import (
 	"text/template"
 
 	"gopkg.in/yaml.v3"
+
+	"project/config"
+	"project/internal/environ"
+	"project/internal/placeholder"
+	"project/internal/yamltree"
 )
 
 type ConfigField struct {
@@ -28,106 +34,359 @@ type ConfigStruct struct {
 }
 
 type YamlNode struct {
-	Key      string
+	Key string
+	// GoName is the field name in the generated struct, from @go-name or
+	// derived from Key.
+	GoName string
+	// TypeName is the type forced by a @type annotation on a section.
+	TypeName string
 	Value    interface{}
-	Children map[string]*YamlNode
+	Children []*YamlNode
 	EnvVars  []string
 	Path     string
+	// Line is the line of the value in the parsed file.
+	Line   int
+	Secret bool
+	// Reload is "reload" or "restart", from the nearest @reload or
+	// @restart annotation.
+	Reload string
+	// Description is the key's comment with annotation lines removed.
+	Description string
+	// Enum lists the allowed values, from an @enum annotation or a comment
+	// such as "# easy, normal, hard" that includes the default.
+	Enum []string
+	// Example is a sample value from an @example annotation.
+	Example string
+	// Default is the literal value, or the placeholder default when the
+	// value is a single placeholder.
+	Default string
+	// Required is set when the leaf references a variable without a
+	// default and is not @optional; RequiredIf holds its @required-if
+	// conditions.
+	Required   bool
+	RequiredIf []string
+}
+
+// FieldMeta is the per-leaf metadata emitted into the generated code.
+type FieldMeta struct {
+	Path   string
+	Secret bool
+	Reload string
+	// Env lists the variables the leaf's value references.
+	Env []string
+	// GoPath is the selector of the leaf below Config, e.g. Web.Port.
+	GoPath string
+	GoType string
+	// Default is the Go expression of the leaf's default value, or "" for
+	// the zero value.
+	Default string
 }
 
+const templatePath = "config/config.yaml.template"
+
+// secretSuffixes mark keys and env vars such as client_secret or
+// DB_PASSWORD as secret even without a @secret annotation.
+var secretSuffixes = []string{"secret", "password", "token"}
+
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run tools/configgen/main.go [generate|validate]")
+		fmt.Println("Usage: go run ./tools/configgen [generate|validate|lint|diff|explain|docs|env|keygen|encrypt|decrypt|rotate-key] [flags]")
 		os.Exit(1)
 	}
 
 	switch os.Args[1] {
 	case "generate":
-		generateConfig()
+		generateConfig(os.Args[2:])
 	case "validate":
-		validateConfig()
+		validateConfig(os.Args[2:])
+	case "lint":
+		lintCommand(os.Args[2:])
+	case "diff":
+		diffCommand(os.Args[2:])
+	case "explain":
+		explainCommand(os.Args[2:])
+	case "docs":
+		docsCommand(os.Args[2:])
+	case "env":
+		envCommand(os.Args[2:])
+	case "keygen":
+		keygenCommand(os.Args[2:])
+	case "encrypt":
+		encryptCommand(os.Args[2:])
+	case "decrypt":
+		decryptCommand(os.Args[2:])
+	case "rotate-key":
+		rotateKeyCommand(os.Args[2:])
 	default:
 		fmt.Printf("Unknown command: %s\n", os.Args[1])
 		os.Exit(1)
 	}
 }
 
-func generateConfig() {
-	templateContent, err := os.ReadFile("config/config.yaml.template")
+func generateConfig(args []string) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	profile := fs.String("profile", os.Getenv("APP_ENV"), "also generate .env.<profile>.example for this profile")
+	naming := readSettings().Naming
+	structNames := fs.String("struct-names", naming.Structs, "struct naming strategy: legacy, nested, leaf or inline")
+	dedupe := fs.Bool("dedupe", naming.Dedupe, "share one type between sections of the same shape and name or @type")
+	fs.Parse(args)
+
+	if !slices.Contains(structNamingStrategies, *structNames) {
+		fmt.Printf("❌ Unknown struct naming strategy %q (want %s)\n", *structNames, strings.Join(structNamingStrategies, ", "))
+		os.Exit(1)
+	}
+
+	templateContent, err := os.ReadFile(templatePath)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to read template: %v", err))
 	}
 
-	var yamlData interface{}
+	var yamlData yaml.Node
 	if err := yaml.Unmarshal(templateContent, &yamlData); err != nil {
 		panic(fmt.Sprintf("Failed to parse YAML: %v", err))
 	}
 
-	root := buildConfigTree(yamlData, "")
+	root := buildConfigTree(&yamlData)
 	envVars := extractEnvVarsFromContent(string(templateContent))
-	structs := generateStructsFromTree(root, envVars)
+	structs, err := generateStructsFromTree(root, *structNames, *dedupe)
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Printf("❌ %s\n", line)
+		}
+		os.Exit(1)
+	}
+
+	generateGoCode(structs, collectFieldMeta(root), envVars)
+	envDocs := collectEnvVarDocs(root, templatePath)
+	generateEnvFiles(envDocs, envVars)
 
-	generateGoCode(structs, envVars)
-	generateEnvFiles(envVars)
+	if *profile != "" {
+		generateProfileEnvExample(*profile, envDocs)
+	}
 
 	fmt.Println("✅ Configuration files generated successfully!")
 }
 
-func buildConfigTree(data interface{}, path string) *YamlNode {
-	node := &YamlNode{
-		Children: make(map[string]*YamlNode),
-		Path:     path,
+func profileOverlayPath(profile string) string {
+	return fmt.Sprintf("config/config.%s.yaml", profile)
+}
+
+func profileEnvExamplePath(profile string) string {
+	return fmt.Sprintf(".env.%s.example", profile)
+}
+
+// readTemplateTree parses the template into a tree of YamlNodes.
+func readTemplateTree() *YamlNode {
+	templateContent, err := os.ReadFile(templatePath)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to read template: %v", err))
+	}
+
+	var yamlData yaml.Node
+	if err := yaml.Unmarshal(templateContent, &yamlData); err != nil {
+		panic(fmt.Sprintf("Failed to parse YAML: %v", err))
+	}
+
+	return buildConfigTree(&yamlData)
+}
+
+// readOverlayTree parses the profile overlay, or returns nil when there is
+// none.
+func readOverlayTree(profile string) *YamlNode {
+	content, err := os.ReadFile(profileOverlayPath(profile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	var yamlData yaml.Node
+	if err := yaml.Unmarshal(content, &yamlData); err != nil {
+		panic(fmt.Sprintf("Failed to parse %s: %v", profileOverlayPath(profile), err))
 	}
+	return buildConfigTree(&yamlData)
+}
 
-	switch v := data.(type) {
-	case map[string]interface{}:
-		for key, value := range v {
-			childPath := key
-			if path != "" {
-				childPath = path + "." + key
+// buildConfigTree converts the parsed template into a tree of YamlNodes in
+// document order. Sequences are kept as leaf values.
+func buildConfigTree(root *yaml.Node) *YamlNode {
+	tree := &YamlNode{}
+	nodes := make(map[*yamltree.Entry]*YamlNode)
+
+	yamltree.Walk(root, func(e *yamltree.Entry) {
+		parent := tree
+		if e.Parent != nil {
+			p, ok := nodes[e.Parent]
+			if !ok {
+				return
 			}
+			parent = p
+		}
+		if e.Key == nil {
+			return
+		}
+
+		node := &YamlNode{Key: e.Key.Value, GoName: entryGoName(e), Path: e.Path, Line: e.Value.Line, Description: commentText(e)}
 
-			child := buildConfigTree(value, childPath)
-			child.Key = key
-			node.Children[key] = child
+		kind := e.Value.Kind
+		if kind == yaml.AliasNode {
+			kind = e.Value.Alias.Kind
 		}
-	case []interface{}:
-		node.Value = data
-	default:
-		node.Value = data
-		if str, ok := data.(string); ok {
-			envVars := extractEnvVarsFromString(str)
-			node.EnvVars = envVars
+		if kind != yaml.MappingNode {
+			if err := e.Value.Decode(&node.Value); err != nil {
+				panic(fmt.Sprintf("Failed to decode %s: %v", e.Path, err))
+			}
+			if kind == yaml.ScalarNode {
+				node.EnvVars = extractEnvVarsFromString(e.Value.Value)
+				node.Secret = isSecret(e, node.EnvVars)
+				node.Default, node.Required = scalarDefault(e)
+				if node.Required {
+					for _, directive := range e.All("required-if") {
+						node.RequiredIf = append(node.RequiredIf, directive.Args)
+					}
+				}
+			}
+			node.Reload = reloadClass(e)
+			node.Enum = enumValues(e, node)
+			if directive, ok := e.Directives.Lookup("example"); ok {
+				node.Example = directive.Args
+			}
+			if node.Enum != nil && !e.Directives.Has("enum") {
+				node.Description = ""
+			}
 		}
+
+		if directive, ok := e.Directives.Lookup("type"); ok {
+			node.TypeName = directive.Args
+		}
+
+		nodes[e] = node
+		parent.Children = append(parent.Children, node)
+	})
+
+	return tree
+}
+
+// isSecret reports whether a leaf is annotated with @secret, directly or
+// through a parent section, or is named like a secret.
+func isSecret(e *yamltree.Entry, envVars []string) bool {
+	if _, ok := e.Inherited("secret"); ok {
+		return true
 	}
 
-	return node
+	return slices.ContainsFunc(append([]string{e.Key.Value}, envVars...), isSecretName)
 }
 
-func extractEnvVarsFromString(s string) []string {
-	re := regexp.MustCompile(`\$\{([^}:]+)(?::([^}]*))?\}`)
-	matches := re.FindAllStringSubmatch(s, -1)
+// reloadClass returns "restart" when the nearest @reload or @restart
+// annotation on e or its parent sections is @restart, and "reload"
+// otherwise.
+func reloadClass(e *yamltree.Entry) string {
+	for cur := e; cur != nil; cur = cur.Parent {
+		if cur.Directives.Has("restart") {
+			return "restart"
+		}
+		if cur.Directives.Has("reload") {
+			return "reload"
+		}
+	}
+	return "reload"
+}
 
+// commentText returns the comments written on e without their annotation
+// lines, joined into one line.
+func commentText(e *yamltree.Entry) string {
+	var lines []string
+	for _, comment := range []string{e.Key.HeadComment, e.Key.LineComment, e.Value.LineComment} {
+		for _, line := range strings.Split(comment, "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+			if line != "" && !strings.HasPrefix(line, "@") {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// enumValues returns the allowed values of a leaf from its @enum annotation,
+// or from a comma-separated comment that lists the leaf's default among
+// them.
+func enumValues(e *yamltree.Entry, node *YamlNode) []string {
+	list := node.Description
+	if directive, ok := e.Directives.Lookup("enum"); ok {
+		list = directive.Args
+	} else if !strings.Contains(list, ",") {
+		return nil
+	}
+
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" || strings.ContainsAny(value, " \t") {
+			return nil
+		}
+		values = append(values, value)
+	}
+
+	if !e.Directives.Has("enum") && !slices.Contains(values, node.Default) {
+		return nil
+	}
+	return values
+}
+
+// scalarDefault returns the value a scalar leaf has when no variable is set,
+// and whether it references a variable that must be set. A value made of a
+// single placeholder yields that placeholder's default; other values are
+// returned as written.
+func scalarDefault(e *yamltree.Entry) (string, bool) {
+	t, err := placeholder.Parse(e.Value.Value)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to parse placeholders in %s: %v", e.Path, err))
+	}
+
+	required := false
+	if _, optional := e.Inherited("optional"); !optional {
+		required = slices.ContainsFunc(t.Vars(), (*placeholder.Ref).Required)
+	}
+
+	if len(t) == 1 && t[0].Ref != nil {
+		return t[0].Ref.Default(), required
+	}
+	return e.Value.Value, required
+}
+
+func isSecretName(name string) bool {
+	parts := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	return len(parts) > 0 && slices.Contains(secretSuffixes, parts[len(parts)-1])
+}
+
+// parsePlaceholders returns the variable references in s. Scheme references
+// such as ${file:/path} do not name environment variables and are skipped.
+func parsePlaceholders(s string) []*placeholder.Ref {
+	t, err := placeholder.Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to parse placeholders: %v", err))
+	}
+	return t.Vars()
+}
+
+func extractEnvVarsFromString(s string) []string {
 	var vars []string
-	for _, match := range matches {
-		vars = append(vars, match[1])
+	for _, ref := range parsePlaceholders(s) {
+		vars = append(vars, ref.Name)
 	}
 	return vars
 }
 
 func extractEnvVarsFromContent(content string) []ConfigField {
-	re := regexp.MustCompile(`\$\{([^}:]+)(?::([^}]*))?\}`)
-	matches := re.FindAllStringSubmatch(content, -1)
-
 	var fields []ConfigField
 	seen := make(map[string]bool)
 
-	for _, match := range matches {
-		envVar := match[1]
-		defaultValue := ""
-		if len(match) > 2 {
-			defaultValue = match[2]
-		}
+	for _, ref := range parsePlaceholders(content) {
+		envVar := ref.Name
+		defaultValue := ref.Default()
 
 		if seen[envVar] {
 			continue
@@ -139,7 +398,7 @@ func extractEnvVarsFromContent(content string) []ConfigField {
 			EnvVar:       envVar,
 			GoType:       inferGoType(defaultValue),
 			DefaultValue: defaultValue,
-			Required:     defaultValue == "",
+			Required:     ref.Required(),
 			YamlPath:     getYamlPathFromEnvVar(content, envVar),
 		}
 
@@ -149,40 +408,37 @@ func extractEnvVarsFromContent(content string) []ConfigField {
 	return fields
 }
 
-func generateStructsFromTree(node *YamlNode, envVars []ConfigField) []ConfigStruct {
-	var allStructs []ConfigStruct
-	structMap := make(map[string]bool)
-
-	mainStruct := ConfigStruct{
-		Name:   "Config",
-		Fields: []ConfigField{},
-	}
-
-	for key, child := range node.Children {
-		if shouldSkipField(child) {
-			continue
-		}
-
-		structName := strings.Title(toCamelCase(key))
-
-		childStructs := generateStructFromNode(child, structName, envVars, key, structMap)
-
-		if len(childStructs) > 0 && len(childStructs[0].Fields) > 0 {
-			allStructs = append(allStructs, childStructs...)
-
-			mainStruct.Fields = append(mainStruct.Fields, ConfigField{
-				Name:     structName,
-				GoType:   structName + "Struct",
-				YamlPath: key,
-				Tags:     fmt.Sprintf("`koanf:\"%s\"`", key),
+// collectFieldMeta lists the leaves that end up in the generated structs.
+func collectFieldMeta(root *YamlNode) []FieldMeta {
+	var fields []FieldMeta
+
+	var collect func(node *YamlNode, goPath string)
+	collect = func(node *YamlNode, goPath string) {
+		if len(node.Children) == 0 {
+			goType := leafGoType(node)
+			fields = append(fields, FieldMeta{
+				Path:    node.Path,
+				Secret:  node.Secret,
+				Reload:  node.Reload,
+				Env:     uniqueStrings(node.EnvVars),
+				GoPath:  goPath,
+				GoType:  goType,
+				Default: goDefault(node, goType),
 			})
+			return
+		}
+		for _, child := range node.Children {
+			collect(child, goPath+"."+child.GoName)
 		}
 	}
 
-	result := []ConfigStruct{mainStruct}
-	result = append(result, allStructs...)
+	for _, child := range root.Children {
+		if !shouldSkipField(child) && len(child.Children) > 0 {
+			collect(child, child.GoName)
+		}
+	}
 
-	return result
+	return fields
 }
 
 func shouldSkipField(node *YamlNode) bool {
@@ -192,89 +448,27 @@ func shouldSkipField(node *YamlNode) bool {
 	return false
 }
 
-func generateStructFromNode(node *YamlNode, structName string, envVars []ConfigField, yamlPath string, structMap map[string]bool) []ConfigStruct {
-	if structMap[structName] {
-		return []ConfigStruct{}
-	}
-	structMap[structName] = true
-
-	var allStructs []ConfigStruct
-
-	struct_ := ConfigStruct{
-		Name:   structName,
-		Fields: []ConfigField{},
-	}
-
-	for key, child := range node.Children {
-		fieldName := strings.Title(toCamelCase(key))
-		childPath := yamlPath + "." + key
-
-		if len(child.Children) > 0 {
-			childStructName := structName + fieldName
-
-			childStructs := generateStructFromNode(child, childStructName, envVars, childPath, structMap)
-			allStructs = append(allStructs, childStructs...)
-
-			struct_.Fields = append(struct_.Fields, ConfigField{
-				Name:     fieldName,
-				GoType:   childStructName + "Struct",
-				YamlPath: key,
-				Tags:     fmt.Sprintf("`koanf:\"%s\"`", key),
-			})
-		} else {
-			goType := "string"
-			envVar := ""
-
-			for _, env := range envVars {
-				if matchesEnvVar(childPath, env.EnvVar) {
-					goType = env.GoType
-					envVar = env.EnvVar
-					break
-				}
-			}
-
-			if child.Value != nil {
-				goType = inferGoTypeFromValue(child.Value)
-			}
-
-			tags := fmt.Sprintf("`koanf:\"%s\"`", key)
-			if envVar != "" {
-				tags = fmt.Sprintf("`koanf:\"%s\" env:\"%s\"`", key, envVar)
-			}
-
-			struct_.Fields = append(struct_.Fields, ConfigField{
-				Name:     fieldName,
-				GoType:   goType,
-				YamlPath: key,
-				EnvVar:   envVar,
-				Tags:     tags,
-			})
+func uniqueStrings(values []string) []string {
+	var unique []string
+	for _, v := range values {
+		if !slices.Contains(unique, v) {
+			unique = append(unique, v)
 		}
 	}
-
-	if len(struct_.Fields) > 0 {
-		result := []ConfigStruct{struct_}
-		result = append(result, allStructs...)
-		return result
-	}
-
-	return allStructs
+	return unique
 }
 
-func matchesEnvVar(yamlPath, envVar string) bool {
-	pathParts := strings.Split(strings.ToLower(yamlPath), ".")
-	envParts := strings.Split(strings.ToLower(envVar), "_")
-
-	for _, envPart := range envParts {
-		if slices.Contains(pathParts, envPart) {
-			return true
-		}
+// leafGoType returns the Go type of a leaf field. Secret scalars use the
+// redacting Secret type.
+func leafGoType(node *YamlNode) string {
+	goType := "string"
+	if node.Value != nil {
+		goType = inferGoTypeFromValue(node.Value)
 	}
-
-	pathStr := strings.Join(pathParts, "")
-	envStr := strings.Join(envParts, "")
-
-	return strings.Contains(envStr, pathStr) || strings.Contains(pathStr, envStr)
+	if node.Secret && !strings.HasPrefix(goType, "[]") {
+		goType = "Secret"
+	}
+	return goType
 }
 
 func inferGoTypeFromValue(value interface{}) string {
@@ -304,22 +498,6 @@ func inferGoTypeFromValue(value interface{}) string {
 	}
 }
 
-func toCamelCase(s string) string {
-	parts := strings.FieldsFunc(s, func(r rune) bool {
-		return r == '-' || r == '_'
-	})
-
-	for i, part := range parts {
-		parts[i] = strings.Title(strings.ToLower(part))
-	}
-
-	return strings.Join(parts, "")
-}
-
-func envVarToFieldName(envVar string) string {
-	return toCamelCase(strings.ToLower(envVar))
-}
-
 func inferGoType(defaultValue string) string {
 	if defaultValue == "" {
 		return "string"
@@ -357,55 +535,62 @@ func extractYamlPath(line string) string {
 	return ""
 }
 
-func generateGoCode(structs []ConfigStruct, envVars []ConfigField) {
+func generateGoCode(structs []ConfigStruct, fields []FieldMeta, envVars []ConfigField) {
 	tmpl := `// Code generated by configgen. DO NOT EDIT.
 package config
 
-import (
-	"fmt"
-	"strings"
-	"github.com/knadh/koanf/v2"
-	"github.com/knadh/koanf/parsers/yaml"
-	"github.com/knadh/koanf/providers/env"
-	"github.com/knadh/koanf/providers/file"
-)
+import _ "embed"
 
 {{range .Structs}}
-type {{.Name}}{{if ne .Name "Config"}}Struct{{end}} struct {
+type {{.Name}} struct {
 {{range .Fields}}	{{.Name}} {{.GoType}} {{.Tags}}
 {{end}}}
 
 {{end}}
+var generatedFields = []FieldInfo{
+{{range .Fields}}	{Path: "{{.Path}}", Secret: {{.Secret}}, Reload: {{if eq .Reload "restart"}}RestartRequired{{else}}Reloadable{{end}}{{with .Env}}, Env: []string{ {{- range $i, $v := .}}{{if $i}}, {{end}}"{{$v}}"{{end}}}{{end}}},
+{{end}}}
 
-func NewConfig() (*Config, error) {
-	k := koanf.New(".")
-	
-	if err := k.Load(file.Provider("config/config.yaml"), yaml.Parser()); err != nil {
-		return nil, fmt.Errorf("error loading config file: %w", err)
-	}
-
-	if err := k.Load(env.Provider("", ".", func(s string) string {
-		return strings.Replace(strings.ToLower(s), "_", ".", -1)
-	}), nil); err != nil {
-		return nil, fmt.Errorf("error loading env vars: %w", err)
-	}
+// Template is the configuration template this file was generated from.
+// LoadConfig falls back to it when there is no config file on disk.
+//
+//go:embed config.yaml.template
+var Template string
 
+// Defaults returns the configuration the template describes when no
+// environment variables are set.
+func Defaults() *Config {
 	var cfg Config
-	if err := k.Unmarshal("", &cfg); err != nil {
-		return nil, fmt.Errorf("error unmarshaling config: %w", err)
-	}
+{{range .Fields}}{{if .Default}}	cfg.{{.GoPath}} = {{.Default}}
+{{end}}{{end}}	return &cfg
+}
 
-	return &cfg, nil
+// Diff lists the settings that differ between a and b, in template order.
+func Diff(a, b *Config) []Change {
+	var changes []Change
+{{range .Fields}}	{{if hasPrefix .GoType "[]"}}diffSlice{{else}}diffValue{{end}}(&changes, "{{.Path}}", a.{{.GoPath}}, b.{{.GoPath}})
+{{end}}	return changes
+}
+
+// NewConfig loads the configuration like LoadConfig with default options.
+//
+// Deprecated: Use LoadConfig.
+func NewConfig() (*Config, error) {
+	return LoadConfig()
 }
 `
 
 	data := struct {
 		Structs []ConfigStruct
+		Fields  []FieldMeta
 	}{
 		Structs: structs,
+		Fields:  fields,
 	}
 
-	t := template.Must(template.New("config").Parse(tmpl))
+	t := template.Must(template.New("config").Funcs(template.FuncMap{
+		"hasPrefix": strings.HasPrefix,
+	}).Parse(tmpl))
 
 	file, err := os.Create("config/config.go")
 	if err != nil {
@@ -418,36 +603,25 @@ func NewConfig() (*Config, error) {
 	}
 }
 
-func generateEnvFiles(fields []ConfigField) {
-	generateEnvExample(fields)
+func generateEnvFiles(docs []envVarDoc, fields []ConfigField) {
+	generateEnvExample(docs)
 	generateEnvLocal(fields)
 }
 
-func generateEnvExample(fields []ConfigField) {
-	file, err := os.Create(".env.example")
-	if err != nil {
-		panic(err)
-	}
-	defer file.Close()
-
-	file.WriteString("# Generated environment variables\n")
-	file.WriteString("# Copy this file to .env.local and fill in your values\n\n")
-
-	sort.Slice(fields, func(i, j int) bool {
-		return fields[i].EnvVar < fields[j].EnvVar
-	})
+func generateEnvExample(docs []envVarDoc) {
+	writeEnvExample(".env.example", docs)
+}
 
-	for _, field := range fields {
-		if field.Required {
-			fmt.Fprintf(file, "%s=\n", field.EnvVar)
-		} else {
-			fmt.Fprintf(file, "%s=%s\n", field.EnvVar, field.DefaultValue)
-		}
-	}
+// generateProfileEnvExample writes .env.<profile>.example from the template
+// variables, letting placeholders in config.<profile>.yaml add variables or
+// override their defaults.
+func generateProfileEnvExample(profile string, docs []envVarDoc) {
+	writeEnvExample(profileEnvExamplePath(profile), mergeEnvVarDocs(docs, overlayEnvVarDocs(profile)))
 }
 
 func generateEnvLocal(fields []ConfigField) {
 	if _, err := os.Stat(".env.local"); err == nil {
+		fmt.Println("ℹ️  .env.local exists; run `configgen env sync` to add new variables")
 		return
 	}
 
@@ -465,39 +639,221 @@ func generateEnvLocal(fields []ConfigField) {
 	}
 }
 
-func validateConfig() {
-	fmt.Println("🔍 Validating configuration...")
-
-	templateFields := extractEnvVarsFromTemplate()
-	envFields := extractEnvVarsFromEnvFile()
+func validateConfig(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	flags := addEnvFlags(fs, "environment profile to validate")
+	checkEnv := fs.Bool("env", false, "check the process environment and dotenv files instead of .env.example")
+	strict := fs.Bool("strict", false, "fail on warnings too")
+	format := fs.String("format", "text", "output format: text, json, sarif or junit")
+	fs.Parse(args)
+	profile := *flags.profile
 
-	missing := findMissingVars(templateFields, envFields)
-	if len(missing) > 0 {
-		fmt.Printf("❌ Missing environment variables: %v\n", missing)
+	env, err := config.Environment(flags.options()...)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
 		os.Exit(1)
 	}
 
-	extra := findExtraVars(templateFields, envFields)
-	if len(extra) > 0 {
-		fmt.Printf("⚠️  Extra environment variables: %v\n", extra)
+	if *format == "text" {
+		fmt.Println("🔍 Validating configuration...")
+		if profile != "" {
+			fmt.Printf("Profile: %s\n", profile)
+		}
+		reportSecretSources(env)
+	}
+
+	var findings []finding
+	if *checkEnv {
+		findings = validateEnv(profile, env)
+	} else {
+		findings = validateEnvFiles(profile)
+	}
+	findings = append(findings, checkUnknownKeys(profile)...)
+
+	printFindings(*format, "Configuration validation", validateRules, findings, *strict)
+	os.Exit(exitCode(findings, *strict))
+}
+
+// validateEnvFiles compares the variables the template references with
+// those declared by the example file and set in the dotenv files.
+func validateEnvFiles(profile string) []finding {
+	docs := collectEnvVarDocs(readTemplateTree(), templatePath)
+	if profile != "" {
+		docs = mergeEnvVarDocs(docs, overlayEnvVarDocs(profile))
+	}
+	byName := make(map[string]envVarDoc, len(docs))
+	for _, doc := range docs {
+		byName[doc.Name] = doc
+	}
+
+	templateFields := extractEnvVarsFromTemplate(profile)
+	envFields := extractEnvVarsFromEnvFiles(profile)
+
+	var findings []finding
+	for _, name := range findMissingVars(templateFields, envFields) {
+		doc := byName[name]
+		findings = append(findings, finding{
+			Rule: ruleMissingEnv, Severity: severityError, Var: name, Path: doc.Path, File: doc.File, Line: doc.Line,
+			Message: fmt.Sprintf("Missing environment variable %s", name),
+		})
+	}
+
+	for _, name := range findExtraVars(templateFields, envFields) {
+		file, line := envFileLocation(profile, name)
+		findings = append(findings, finding{
+			Rule: ruleExtraEnv, Severity: severityWarning, Var: name, File: file, Line: line,
+			Message: fmt.Sprintf("Extra environment variable %s", name),
+		})
+	}
+
+	return findings
+}
+
+// envFileLocation returns the first of the example and dotenv files that
+// declares name, with the line.
+func envFileLocation(profile, name string) (string, int) {
+	files := []string{".env.example"}
+	if profile != "" {
+		files = append([]string{profileEnvExamplePath(profile)}, files...)
+	}
+	dotenv := environ.Files(profile)
+	for i := len(dotenv) - 1; i >= 0; i-- {
+		files = append(files, dotenv[i])
+	}
+
+	for _, file := range files {
+		if line := dotenvLine(file, name); line > 0 {
+			return file, line
+		}
+	}
+	return "", 0
+}
+
+// checkUnknownKeys reports settings in config.yaml and the profile overlay
+// that the template does not define. The loader ignores them, so they are
+// usually typos.
+func checkUnknownKeys(profile string) []finding {
+	known, _ := readLeaves(templatePath)
+
+	files := []string{"config/config.yaml"}
+	if profile != "" {
+		files = append(files, profileOverlayPath(profile))
+	}
+
+	var findings []finding
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+
+		leaves, order := readLeaves(file)
+		for _, path := range order {
+			if _, ok := known[path]; ok {
+				continue
+			}
+			findings = append(findings, finding{
+				Rule: ruleUnknownKey, Severity: severityWarning, Path: path, File: file, Line: leaves[path].line,
+				Message: fmt.Sprintf("Unknown setting %s", path),
+			})
+		}
 	}
 
-	fmt.Println("✅ Configuration validation passed!")
+	return findings
 }
 
-func extractEnvVarsFromTemplate() []string {
-	content, err := os.ReadFile("config/config.yaml.template")
+// reportSecretSources prints where each secret referenced by the template
+// would be resolved from in env: the process environment, a dotenv file, a
+// NAME_FILE variable, a secrets directory or a ${file:...} placeholder.
+func reportSecretSources(env environ.Env) {
+	content, err := os.ReadFile(templatePath)
 	if err != nil {
 		panic(err)
 	}
 
-	re := regexp.MustCompile(`\$\{([^}:]+)(?::([^}]*))?\}`)
-	matches := re.FindAllStringSubmatch(string(content), -1)
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		panic(fmt.Sprintf("Failed to parse YAML: %v", err))
+	}
+
+	var lines []string
+	seen := make(map[string]bool)
+	yamltree.Walk(&root, func(e *yamltree.Entry) {
+		if !e.IsLeaf() || e.Key == nil {
+			return
+		}
+
+		t, err := placeholder.Parse(e.Value.Value)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to parse placeholders: %v", err))
+		}
+
+		_, annotated := e.Inherited("secret")
+		secretLeaf := annotated || isSecretName(e.Key.Value)
+
+		for _, ref := range t.Refs() {
+			if ref.Scheme == "" && !secretLeaf && !isSecretName(ref.Name) {
+				continue
+			}
+
+			name := ref.Name
+			if ref.Scheme != "" {
+				name = ref.Scheme + ":" + ref.Raw
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			switch {
+			case ref.Scheme == "file":
+				if _, err := os.Stat(ref.Raw); err != nil {
+					lines = append(lines, fmt.Sprintf("  ⚠️  %s: %v", name, err))
+				} else {
+					lines = append(lines, fmt.Sprintf("  %s: file", name))
+				}
+			case ref.Scheme != "":
+				lines = append(lines, fmt.Sprintf("  %s: %s resolver", name, ref.Scheme))
+			default:
+				_, origin, ok, err := env.Resolve(name)
+				switch {
+				case err != nil:
+					lines = append(lines, fmt.Sprintf("  ⚠️  %s: %v", name, err))
+				case !ok && ref.HasDefault():
+					lines = append(lines, fmt.Sprintf("  %s: template default", name))
+				case !ok:
+					lines = append(lines, fmt.Sprintf("  ⚠️  %s: not set", name))
+				default:
+					lines = append(lines, fmt.Sprintf("  %s: %s", name, origin))
+				}
+			}
+		}
+	})
+
+	if len(lines) > 0 {
+		fmt.Println("🔐 Secret sources:")
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+	}
+}
+
+func extractEnvVarsFromTemplate(profile string) []string {
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		panic(err)
+	}
+
+	if profile != "" {
+		overlay, err := os.ReadFile(profileOverlayPath(profile))
+		if err != nil && !os.IsNotExist(err) {
+			panic(err)
+		}
+		content = append(append(content, '\n'), overlay...)
+	}
 
 	var vars []string
 	seen := make(map[string]bool)
-	for _, match := range matches {
-		envVar := match[1]
+	for _, envVar := range extractEnvVarsFromString(string(content)) {
 		if !seen[envVar] {
 			vars = append(vars, envVar)
 			seen[envVar] = true
@@ -507,8 +863,36 @@ func extractEnvVarsFromTemplate() []string {
 	return vars
 }
 
-func extractEnvVarsFromEnvFile() []string {
-	content, err := os.ReadFile(".env.example")
+// extractEnvVarsFromEnvFiles returns the variables declared by the example
+// file for profile together with those set in the profile's dotenv files.
+func extractEnvVarsFromEnvFiles(profile string) []string {
+	exampleFile := ".env.example"
+	if profile != "" {
+		if _, err := os.Stat(profileEnvExamplePath(profile)); err == nil {
+			exampleFile = profileEnvExamplePath(profile)
+		}
+	}
+
+	vars := extractEnvVarsFromEnvFile(exampleFile)
+
+	dotenv, err := environ.Dotenv(profile)
+	if err != nil {
+		panic(err)
+	}
+
+	var defined []string
+	for name := range dotenv.Vars() {
+		if !slices.Contains(vars, name) {
+			defined = append(defined, name)
+		}
+	}
+	sort.Strings(defined)
+
+	return append(vars, defined...)
+}
+
+func extractEnvVarsFromEnvFile(filename string) []string {
+	content, err := os.ReadFile(filename)
 	if err != nil {
 		return []string{}
 	}
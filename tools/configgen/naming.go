@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"project/internal/yamltree"
+)
+
+// configPackageDir holds the package the generated code is written to.
+const configPackageDir = "config"
+
+// commonInitialisms are words written in upper case in Go names, such as
+// the URL in BaseURL. Projects add their own in .configgen.yaml.
+var commonInitialisms = []string{
+	"ACL", "API", "ASCII", "CORS", "CPU", "CSS", "DB", "DNS", "EOF", "GUID",
+	"HTML", "HTTP", "HTTPS", "ID", "IP", "JSON", "JWT", "OS", "QPS", "RAM",
+	"RPC", "SLA", "SMTP", "SQL", "SSH", "SSL", "TCP", "TLS", "TTL", "UDP",
+	"UI", "UID", "URI", "URL", "UTF8", "UUID", "VM", "XML", "XSRF", "XSS",
+}
+
+// initialisms is the set of commonInitialisms and the project's own
+// naming.initialisms.
+var initialisms = sync.OnceValue(func() map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range append(commonInitialisms, readSettings().Naming.Initialisms...) {
+		set[strings.ToUpper(word)] = true
+	}
+	return set
+})
+
+// generatedIdentifiers are declared by the generated config.go itself.
+var generatedIdentifiers = []string{"Config", "Defaults", "Diff", "NewConfig", "Template", "generatedFields"}
+
+// toCamelCase joins the words of s with each word capitalised, or upper
+// case if it is an initialism. Letters and digits of any script are kept;
+// combining marks are dropped and any other character separates words. s is
+// normalised to NFC first, so a decomposed "école" keeps its accent just like
+// the precomposed one.
+func toCamelCase(s string) string {
+	parts := strings.FieldsFunc(norm.NFC.String(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+
+	for i, part := range parts {
+		if upper := strings.ToUpper(part); initialisms()[upper] {
+			parts[i] = upper
+		} else {
+			parts[i] = capitalize(part)
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+// capitalize upper-cases the first letter of word and lower-cases the rest.
+// Unlike strings.Title it maps a leading title-case letter such as ǅ to
+// upper case, which Go requires for an exported name, and it drops
+// combining marks, which Go identifiers cannot contain.
+func capitalize(word string) string {
+	var b strings.Builder
+	first := true
+	for _, r := range word {
+		switch {
+		case unicode.IsMark(r):
+			continue
+		case first:
+			r = unicode.ToUpper(r)
+			first = false
+		default:
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// goFieldName returns the exported Go name for a YAML key. Names that would
+// not start with an upper-case letter, such as those of "2fa", "" or keys in
+// scripts without case, get an X prefix.
+func goFieldName(key string) string {
+	name := toCamelCase(key)
+	if !token.IsExported(name) {
+		name = "X" + name
+	}
+	return name
+}
+
+// entryGoName returns the Go name of a mapping entry: its @go-name
+// annotation if present, or the name derived from the key.
+func entryGoName(e *yamltree.Entry) string {
+	if directive, ok := e.Directives.Lookup("go-name"); ok {
+		return directive.Args
+	}
+	return goFieldName(e.Key.Value)
+}
+
+func envVarToFieldName(envVar string) string {
+	return toCamelCase(strings.ToLower(envVar))
+}
+
+// packageIdentifiers returns the package-level names declared by the
+// hand-written files of the config package, which generated types must not
+// reuse.
+func packageIdentifiers() []string {
+	names := append([]string(nil), generatedIdentifiers...)
+
+	files, err := filepath.Glob(filepath.Join(configPackageDir, "*.go"))
+	if err != nil {
+		panic(err)
+	}
+
+	fset := token.NewFileSet()
+	for _, filename := range files {
+		if filepath.Base(filename) == "config.go" || strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+		if err != nil {
+			panic(fmt.Sprintf("Failed to parse %s: %v", filename, err))
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					names = append(names, decl.Name.Name)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						names = append(names, spec.Name.Name)
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							names = append(names, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGoFieldName(t *testing.T) {
+	tests := []struct {
+		key       string
+		camelCase string
+		fieldName string
+	}{
+		{"max_players", "MaxPlayers", "MaxPlayers"},
+		{"max-players.count", "MaxPlayersCount", "MaxPlayersCount"},
+		{"HELLO_world", "HelloWorld", "HelloWorld"},
+
+		// Initialisms.
+		{"user_id", "UserID", "UserID"},
+		{"api_url", "APIURL", "APIURL"},
+		{"cors_enabled", "CORSEnabled", "CORSEnabled"},
+		{"idle", "Idle", "Idle"},
+
+		// Other scripts.
+		{"имя_пользователя", "ИмяПользователя", "ИмяПользователя"},
+		{"名前", "名前", "X名前"},
+
+		// Title-case letters become upper case.
+		{"ǅungla", "Ǆungla", "Ǆungla"},
+		{"ǆungla", "Ǆungla", "Ǆungla"},
+
+		// Precomposed and decomposed accents give the same name; marks
+		// without a precomposed form are dropped.
+		{"école", "École", "École"},
+		{"e\u0301cole", "École", "École"},
+		{"q\u0301uery", "Query", "Query"},
+
+		// Ligatures have no single upper-case letter.
+		{"ﬁle", "ﬁle", "Xﬁle"},
+
+		// Leading digits, empty and punctuation-only keys.
+		{"2fa", "2fa", "X2fa"},
+		{"", "", "X"},
+		{"___", "", "X"},
+		{"-.-", "", "X"},
+	}
+
+	for _, tt := range tests {
+		if got := toCamelCase(tt.key); got != tt.camelCase {
+			t.Errorf("toCamelCase(%q) = %q, want %q", tt.key, got, tt.camelCase)
+		}
+		if got := goFieldName(tt.key); got != tt.fieldName {
+			t.Errorf("goFieldName(%q) = %q, want %q", tt.key, got, tt.fieldName)
+		}
+	}
+}
+
+// parseTestTree builds the config tree of a template.
+func parseTestTree(t *testing.T, template string) *YamlNode {
+	t.Helper()
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(template), &root); err != nil {
+		t.Fatal(err)
+	}
+	return buildConfigTree(&root)
+}
+
+// describeStructs renders each struct as Name{Field Type, ...}, with
+// anonymous struct types shown as struct.
+func describeStructs(structs []ConfigStruct) []string {
+	var out []string
+	for _, s := range structs {
+		var fields []string
+		for _, f := range s.Fields {
+			goType := f.GoType
+			if strings.HasPrefix(goType, "struct {") {
+				goType = "struct"
+			}
+			fields = append(fields, f.Name+" "+goType)
+		}
+		out = append(out, fmt.Sprintf("%s{%s}", s.Name, strings.Join(fields, ", ")))
+	}
+	return out
+}
+
+func TestStructNamingStrategies(t *testing.T) {
+	const template = `
+game:
+  world:
+    name: Earth
+  player:
+    health: 100
+web:
+  world:
+    size: 2
+  port: 8080
+`
+
+	tests := []struct {
+		strategy string
+		want     []string
+	}{
+		{structNamesLegacy, []string{
+			"Config{Game GameStruct, Web WebStruct}",
+			"GameStruct{World GameWorldStruct, Player GamePlayerStruct}",
+			"GameWorldStruct{Name string}",
+			"GamePlayerStruct{Health int}",
+			"WebStruct{World WebWorldStruct, Port int}",
+			"WebWorldStruct{Size int}",
+		}},
+		{structNamesNested, []string{
+			"Config{Game Game, Web Web}",
+			"Game{World GameWorld, Player GamePlayer}",
+			"GameWorld{Name string}",
+			"GamePlayer{Health int}",
+			"Web{World WebWorld, Port int}",
+			"WebWorld{Size int}",
+		}},
+		// The second world is prefixed with its parent to stay unique.
+		{structNamesLeaf, []string{
+			"Config{Game Game, Web Web}",
+			"Game{World World, Player Player}",
+			"World{Name string}",
+			"Player{Health int}",
+			"Web{World WebWorld, Port int}",
+			"WebWorld{Size int}",
+		}},
+		{structNamesInline, []string{
+			"Config{Game struct, Web struct}",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			structs, err := generateStructsFromTree(parseTestTree(t, template), tt.strategy, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := describeStructs(structs); !slices.Equal(got, tt.want) {
+				t.Errorf("structs:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestStructTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		// want lists the declared structs, or wantErr the error.
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "shared",
+			template: `
+google: # @type OAuthProvider
+  client_id: "${GOOGLE_CLIENT_ID}"
+discord: # @type OAuthProvider
+  client_id: "${DISCORD_CLIENT_ID}"
+`,
+			want: []string{
+				"Config{Google OAuthProvider, Discord OAuthProvider}",
+				"OAuthProvider{ClientID string}",
+			},
+		},
+		{
+			name: "overrides strategy",
+			template: `
+game:
+  world: # @type Planet
+    name: Earth
+`,
+			want: []string{
+				"Config{Game Game}",
+				"Game{World Planet}",
+				"Planet{Name string}",
+			},
+		},
+		{
+			name: "different fields",
+			template: `
+google: # @type OAuthProvider
+  client_id: "${GOOGLE_CLIENT_ID}"
+discord: # @type OAuthProvider
+  token: "${DISCORD_TOKEN}"
+`,
+			wantErr: "discord has different fields than google, which also uses @type OAuthProvider",
+		},
+		{
+			name: "generated name",
+			template: `
+game:
+  world:
+    name: Earth
+web: # @type GameWorld
+  port: 8080
+`,
+			wantErr: "game.world and web both map to type GameWorld",
+		},
+		{
+			name: "package identifier",
+			template: `
+game: # @type Defaults
+  name: x
+`,
+			wantErr: "game maps to type Defaults, which the config package already declares",
+		},
+		{
+			name: "not exported",
+			template: `
+game: # @type world
+  name: x
+`,
+			wantErr: `game: @type "world" is not an exported Go identifier`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			structs, err := generateStructsFromTree(parseTestTree(t, tt.template), structNamesNested, false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := describeStructs(structs); !slices.Equal(got, tt.want) {
+				t.Errorf("structs:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestSharedTypeKeepsEnvMetadata(t *testing.T) {
+	root := parseTestTree(t, `
+google: # @type OAuthProvider
+  client_id: "${GOOGLE_CLIENT_ID}"
+discord: # @type OAuthProvider
+  client_id: "${DISCORD_CLIENT_ID}"
+`)
+
+	structs, err := generateStructsFromTree(root, structNamesNested, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The shared type cannot name either section's variable.
+	if tags := structs[1].Fields[0].Tags; tags != "`koanf:\"client_id\"`" {
+		t.Errorf("shared field tags = %s, want no env tag", tags)
+	}
+
+	env := make(map[string][]string)
+	for _, f := range collectFieldMeta(root) {
+		env[f.Path] = f.Env
+	}
+	for path, want := range map[string]string{
+		"google.client_id":  "GOOGLE_CLIENT_ID",
+		"discord.client_id": "DISCORD_CLIENT_ID",
+	} {
+		if got := env[path]; !slices.Equal(got, []string{want}) {
+			t.Errorf("%s env = %v, want [%s]", path, got, want)
+		}
+	}
+}
+
+func TestSectionShape(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{"values differ", "{a: 1, b: x}", "{a: 2, b: y}", true},
+		{"key differs", "{a: 1}", "{c: 1}", false},
+		{"type differs", "{a: 1}", "{a: x}", false},
+		{"order differs", "{a: 1, b: x}", "{b: x, a: 1}", false},
+		{"extra field", "{a: 1}", "{a: 1, b: x}", false},
+		{"nested section", "{a: {b: 1}}", "{a: {b: 2}}", true},
+		{"nested section differs", "{a: {b: 1}}", "{a: {c: 1}}", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := parseTestTree(t, fmt.Sprintf("first: %s\nsecond: %s\n", tt.a, tt.b))
+			a, b := sectionShape(root.Children[0]), sectionShape(root.Children[1])
+			if (a == b) != tt.same {
+				t.Errorf("shapes %s and %s: equal = %v, want %v", a, b, a == b, tt.same)
+			}
+		})
+	}
+}
+
+func TestSectionShapeNamedType(t *testing.T) {
+	// A nested section with a @type is described by its type, which
+	// checks its fields separately.
+	root := parseTestTree(t, `
+first:
+  retry: # @type Retry
+    attempts: 3
+second:
+  retry: # @type Backoff
+    attempts: 3
+`)
+	if a, b := sectionShape(root.Children[0]), sectionShape(root.Children[1]); a == b {
+		t.Errorf("sections with different nested @type have the same shape %s", a)
+	}
+}
+
+func TestGroupSections(t *testing.T) {
+	root := parseTestTree(t, `
+a:
+  retry:
+    attempts: 3
+    delay: 1s
+b:
+  retry:
+    attempts: 5
+    delay: 2s
+  backoff:
+    attempts: 1
+    delay: 5s
+c:
+  enabled: true
+`)
+
+	var got [][]string
+	for _, group := range groupSections(root) {
+		var paths []string
+		for _, node := range group {
+			paths = append(paths, node.Path)
+		}
+		got = append(got, paths)
+	}
+	slices.SortFunc(got, func(x, y []string) int { return strings.Compare(x[0], y[0]) })
+
+	// Sections are grouped at every depth, in document order.
+	want := [][]string{{"a"}, {"a.retry", "b.retry", "b.backoff"}, {"b"}, {"c"}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("groups = %v, want %v", got, want)
+	}
+}
+
+func TestDedupeNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{
+			name: "same name",
+			template: `
+a:
+  retry: {attempts: 3}
+b:
+  retry: {attempts: 5}
+`,
+			want: []string{
+				"Config{A A, B B}",
+				"A{Retry Retry}",
+				"Retry{Attempts int}",
+				"B{Retry Retry}",
+			},
+		},
+		{
+			// Sharing would name b.backoff's type after a.retry.
+			name: "different names",
+			template: `
+a:
+  retry: {attempts: 3}
+b:
+  backoff: {attempts: 5}
+`,
+			want: []string{
+				"Config{A A, B B}",
+				"A{Retry ARetry}",
+				"ARetry{Attempts int}",
+				"B{Backoff BBackoff}",
+				"BBackoff{Attempts int}",
+			},
+		},
+		{
+			name: "named by @type",
+			template: `
+a:
+  retry: {attempts: 3}
+b:
+  backoff: {attempts: 5} # @type Policy
+`,
+			want: []string{
+				"Config{A A, B B}",
+				"A{Retry Policy}",
+				"Policy{Attempts int}",
+				"B{Backoff Policy}",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			structs, err := generateStructsFromTree(parseTestTree(t, tt.template), structNamesNested, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := describeStructs(structs); !slices.Equal(got, tt.want) {
+				t.Errorf("structs:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+const settingsPath = ".configgen.yaml"
+
+// toolSettings is the optional .configgen.yaml in the project root.
+type toolSettings struct {
+	Naming namingSettings `yaml:"naming"`
+	Lint   lintSettings   `yaml:"lint"`
+}
+
+// namingSettings tunes the Go names generated for template keys.
+type namingSettings struct {
+	// Initialisms are extra words written in upper case, like the built-in
+	// URL or ID.
+	Initialisms []string `yaml:"initialisms"`
+	// Structs is the struct naming strategy, legacy by default.
+	Structs string `yaml:"structs"`
+	// Dedupe makes sections of the same shape share one type: the @type
+	// one of them has, or else one per section name.
+	Dedupe bool `yaml:"dedupe"`
+}
+
+// lintSettings selects and configures lint rules. When Enable is set only
+// those rules run; Disable then removes rules from the selection.
+type lintSettings struct {
+	Enable  []string               `yaml:"enable"`
+	Disable []string               `yaml:"disable"`
+	Rules   map[string]ruleOptions `yaml:"rules"`
+}
+
+// ruleOptions configures one lint rule.
+type ruleOptions struct {
+	// Severity overrides the rule's default severity.
+	Severity string `yaml:"severity"`
+	// Allow exempts variable names, keys or paths, depending on the rule.
+	Allow []string `yaml:"allow"`
+}
+
+// readSettings loads .configgen.yaml, returning the defaults when the file
+// does not exist.
+func readSettings() toolSettings {
+	settings := toolSettings{Naming: namingSettings{Structs: structNamesLegacy}}
+
+	content, err := os.ReadFile(settingsPath)
+	if os.IsNotExist(err) {
+		return settings
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	if err := yaml.Unmarshal(content, &settings); err != nil {
+		panic(fmt.Sprintf("Failed to parse %s: %v", settingsPath, err))
+	}
+	return settings
+}
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Strategies for naming the struct types generated for sections, chosen with
+// naming.structs in .configgen.yaml or generate --struct-names. A @type
+// annotation overrides the strategy for its section.
+const (
+	// structNamesLegacy joins the section path and appends Struct, as in
+	// GameWorldStruct.
+	structNamesLegacy = "legacy"
+	// structNamesNested joins the section path, as in GameWorld.
+	structNamesNested = "nested"
+	// structNamesLeaf uses the section's own name, as in World, prefixing
+	// parent names only where needed to keep it unique.
+	structNamesLeaf = "leaf"
+	// structNamesInline declares sections as anonymous structs in place.
+	structNamesInline = "inline"
+)
+
+var structNamingStrategies = []string{structNamesLegacy, structNamesNested, structNamesLeaf, structNamesInline}
+
+// structScope is where a section sits while its type is named.
+type structScope struct {
+	// prefix is the name nested and legacy names start from: the joined
+	// field names of the parents, or the parent's type name without suffix.
+	prefix string
+	// parents are the field names of the enclosing sections.
+	parents []string
+}
+
+// child returns the scope of the fields of a section, whose nested names
+// start from prefix.
+func (s structScope) child(fieldName, prefix string) structScope {
+	return structScope{prefix: prefix, parents: append(slices.Clone(s.parents), fieldName)}
+}
+
+// structBuilder turns the config tree into struct declarations, recording
+// names that are not valid Go and names that two keys would share instead of
+// emitting broken or incomplete code.
+type structBuilder struct {
+	strategy string
+	// groups lists the sections of each shape when sections with the same
+	// shape share a type.
+	groups map[string][]*YamlNode
+	// sharedNames maps share keys to the type their sections share.
+	sharedNames map[string]string
+	// owners maps each type name to the path it was generated for, or to
+	// "" for names the config package already declares.
+	owners map[string]string
+	// shapes holds the shape of each @type, which every section that uses
+	// the type must match.
+	shapes map[string]string
+	// declared indexes structs by type name.
+	declared map[string]int
+	structs  []ConfigStruct
+	errs     []error
+}
+
+func newStructBuilder(strategy string) *structBuilder {
+	owners := make(map[string]string)
+	for _, name := range packageIdentifiers() {
+		owners[name] = ""
+	}
+	return &structBuilder{
+		strategy:    strategy,
+		sharedNames: make(map[string]string),
+		owners:      owners,
+		shapes:      make(map[string]string),
+		declared:    make(map[string]int),
+	}
+}
+
+// generateStructsFromTree returns the Config struct followed by the named
+// struct types of its sections. With dedupe, sections of the same shape share
+// one type when one of them names it with @type, or else with the sections
+// of the same name. Names that collide or are not valid Go are reported as a
+// joined error.
+func generateStructsFromTree(node *YamlNode, strategy string, dedupe bool) ([]ConfigStruct, error) {
+	b := newStructBuilder(strategy)
+	if dedupe {
+		b.groups = groupSections(node)
+	}
+	b.structs = []ConfigStruct{{Name: "Config", Fields: []ConfigField{}}}
+	fieldNames := make(map[string]string)
+
+	for _, child := range node.Children {
+		if shouldSkipField(child) || len(child.Children) == 0 {
+			continue
+		}
+
+		key := child.Key
+		fieldName := b.fieldName(child, fieldNames)
+		b.structs[0].Fields = append(b.structs[0].Fields, ConfigField{
+			Name:     fieldName,
+			GoType:   b.sectionType(child, structScope{}, fieldName, 1),
+			YamlPath: key,
+			Tags:     fmt.Sprintf("`koanf:\"%s\"`", key),
+		})
+	}
+
+	return b.structs, errors.Join(b.errs...)
+}
+
+// sectionType returns the Go type of the field holding a section, declaring
+// named types as needed. depth is the indentation of that field.
+func (b *structBuilder) sectionType(node *YamlNode, scope structScope, fieldName string, depth int) string {
+	shape := sectionShape(node)
+	if node.TypeName != "" {
+		return b.annotatedType(node, shape, scope, fieldName)
+	}
+	if key, ok := b.shareKey(node, shape); ok {
+		return b.sharedType(node, key, shape, scope, fieldName)
+	}
+	if b.strategy == structNamesInline {
+		return b.inlineStruct(node, scope.child(fieldName, scope.prefix+fieldName), depth)
+	}
+
+	base, ok := b.strategyName(node, scope, fieldName, false)
+	if !ok {
+		return base + b.suffix()
+	}
+	return b.declare(base+b.suffix(), node, scope.child(fieldName, base))
+}
+
+// annotatedType returns the type named by a section's @type, declaring it
+// for the first section that uses it.
+func (b *structBuilder) annotatedType(node *YamlNode, shape string, scope structScope, fieldName string) string {
+	typeName := node.TypeName
+	if !token.IsIdentifier(typeName) || !token.IsExported(typeName) {
+		b.errs = append(b.errs, fmt.Errorf("%s: @type %q is not an exported Go identifier", node.Path, typeName))
+		return typeName
+	}
+
+	if first, ok := b.shapes[typeName]; ok {
+		if first != shape {
+			b.errs = append(b.errs, fmt.Errorf("%s has different fields than %s, which also uses @type %s", node.Path, b.owners[typeName], typeName))
+		}
+		b.shareType(typeName)
+		return typeName
+	}
+
+	if !b.claimType(typeName, node.Path) {
+		return typeName
+	}
+	b.shapes[typeName] = shape
+	if len(b.groups[shape]) > 1 {
+		b.sharedNames[shape] = typeName
+	}
+	return b.declare(typeName, node, scope.child(fieldName, typeName))
+}
+
+// shareKey returns the key of the type a section shares with the others of
+// its shape, and whether it shares one. Sections share a type when one of
+// them names it with @type, or else with the sections of the same name, so
+// that no section lends the type a misleading name.
+func (b *structBuilder) shareKey(node *YamlNode, shape string) (string, bool) {
+	group := b.groups[shape]
+	if slices.ContainsFunc(group, func(member *YamlNode) bool { return member.TypeName != "" }) {
+		return shape, len(group) > 1
+	}
+
+	named := 0
+	for _, member := range group {
+		if member.GoName == node.GoName {
+			named++
+		}
+	}
+	return shape + " " + node.GoName, named > 1
+}
+
+// sharedType returns the type shared by the sections with the given share
+// key, declaring it for the first of them. The type takes the @type of any
+// section of the shape, or else the field name the sections have, as in
+// Retry for a.retry and b.retry.
+func (b *structBuilder) sharedType(node *YamlNode, key, shape string, scope structScope, fieldName string) string {
+	if typeName, ok := b.sharedNames[key]; ok {
+		b.shareType(typeName)
+		return typeName
+	}
+
+	for _, member := range b.groups[shape] {
+		if member.TypeName != "" {
+			b.sharedNames[key] = member.TypeName
+			if !b.claimType(member.TypeName, node.Path) {
+				return member.TypeName
+			}
+			b.shapes[member.TypeName] = shape
+			return b.declare(member.TypeName, node, scope.child(fieldName, member.TypeName))
+		}
+	}
+
+	base, ok := b.strategyName(node, scope, fieldName, true)
+	typeName := base + b.suffix()
+	b.sharedNames[key] = typeName
+	if !ok {
+		return typeName
+	}
+	return b.declare(typeName, node, scope.child(fieldName, base))
+}
+
+// strategyName returns the name of a section's type without suffix under the
+// naming strategy, or under the leaf strategy if leaf is set, and whether
+// the name could be claimed. Inline sections that need a named type are
+// named like leaf sections.
+func (b *structBuilder) strategyName(node *YamlNode, scope structScope, fieldName string, leaf bool) (string, bool) {
+	if leaf || b.strategy == structNamesLeaf || b.strategy == structNamesInline {
+		return b.leafTypeName(node, scope, fieldName), true
+	}
+
+	base := scope.prefix + fieldName
+	return base, b.claimType(base+b.suffix(), node.Path)
+}
+
+// suffix returns what the strategy appends to type names.
+func (b *structBuilder) suffix() string {
+	if b.strategy == structNamesLegacy {
+		return "Struct"
+	}
+	return ""
+}
+
+// declare adds the struct type of a section and returns its name.
+func (b *structBuilder) declare(typeName string, node *YamlNode, scope structScope) string {
+	index := len(b.structs)
+	b.structs = append(b.structs, ConfigStruct{Name: typeName})
+	b.declared[typeName] = index
+
+	fields := b.structFields(node, scope, 1)
+	b.structs[index].Fields = fields
+	return typeName
+}
+
+// structFields returns the fields of a section at the given indentation.
+func (b *structBuilder) structFields(node *YamlNode, scope structScope, depth int) []ConfigField {
+	var fields []ConfigField
+	fieldNames := make(map[string]string)
+
+	for _, child := range node.Children {
+		key := child.Key
+		fieldName := b.fieldName(child, fieldNames)
+
+		if len(child.Children) > 0 {
+			fields = append(fields, ConfigField{
+				Name:     fieldName,
+				GoType:   b.sectionType(child, scope, fieldName, depth),
+				YamlPath: key,
+				Tags:     fmt.Sprintf("`koanf:\"%s\"`", key),
+			})
+			continue
+		}
+
+		envVar := strings.Join(uniqueStrings(child.EnvVars), ",")
+
+		tags := fmt.Sprintf("`koanf:\"%s\"`", key)
+		if envVar != "" {
+			tags = fmt.Sprintf("`koanf:\"%s\" env:\"%s\"`", key, envVar)
+		}
+
+		fields = append(fields, ConfigField{
+			Name:     fieldName,
+			GoType:   leafGoType(child),
+			YamlPath: key,
+			EnvVar:   envVar,
+			Tags:     tags,
+		})
+	}
+
+	return fields
+}
+
+// inlineStruct returns an anonymous struct type for a section whose field is
+// indented by depth.
+func (b *structBuilder) inlineStruct(node *YamlNode, scope structScope, depth int) string {
+	var s strings.Builder
+	s.WriteString("struct {\n")
+	for _, f := range b.structFields(node, scope, depth+1) {
+		fmt.Fprintf(&s, "%s%s %s %s\n", strings.Repeat("\t", depth+1), f.Name, f.GoType, f.Tags)
+	}
+	s.WriteString(strings.Repeat("\t", depth) + "}")
+	return s.String()
+}
+
+// leafTypeName names a section after its own field name, adding the names of
+// its parents, nearest first, until the name is unused. It returns the name
+// without suffix.
+func (b *structBuilder) leafTypeName(node *YamlNode, scope structScope, fieldName string) string {
+	names := append(slices.Clone(scope.parents), fieldName)
+	candidates := make([]string, 0, len(names))
+	for i := len(names) - 1; i >= 0; i-- {
+		candidates = append(candidates, strings.Join(names[i:], ""))
+	}
+
+	for n := 2; ; n++ {
+		for _, candidate := range candidates {
+			if _, taken := b.owners[candidate+b.suffix()]; !taken {
+				b.owners[candidate+b.suffix()] = node.Path
+				return candidate
+			}
+		}
+		candidates = []string{fmt.Sprintf("%s%d", fieldName, n)}
+	}
+}
+
+// shareType drops the env tags of a type used by several sections, since
+// each section reads different variables. The variables stay recorded per
+// path in the generated field metadata.
+func (b *structBuilder) shareType(typeName string) {
+	index, ok := b.declared[typeName]
+	if !ok {
+		return
+	}
+	for i, f := range b.structs[index].Fields {
+		if f.EnvVar != "" {
+			b.structs[index].Fields[i].EnvVar = ""
+			b.structs[index].Fields[i].Tags = fmt.Sprintf("`koanf:\"%s\"`", f.YamlPath)
+		}
+	}
+}
+
+// groupSections groups the sections below root by shape, in document order.
+func groupSections(root *YamlNode) map[string][]*YamlNode {
+	groups := make(map[string][]*YamlNode)
+
+	var walk func(node *YamlNode)
+	walk = func(node *YamlNode) {
+		for _, child := range node.Children {
+			if len(child.Children) > 0 {
+				shape := sectionShape(child)
+				groups[shape] = append(groups[shape], child)
+				walk(child)
+			}
+		}
+	}
+	walk(root)
+
+	return groups
+}
+
+// sectionShape fingerprints a section by its fields: their names, keys and
+// Go types, with nested sections described in place unless they have a
+// @type. Sections with the same shape can share a type.
+func sectionShape(node *YamlNode) string {
+	var s strings.Builder
+	s.WriteString("{")
+	for _, child := range node.Children {
+		fmt.Fprintf(&s, "%s %q ", child.GoName, child.Key)
+		switch {
+		case child.TypeName != "":
+			s.WriteString(child.TypeName)
+		case len(child.Children) > 0:
+			s.WriteString(sectionShape(child))
+		default:
+			s.WriteString(leafGoType(child))
+		}
+		s.WriteString("; ")
+	}
+	s.WriteString("}")
+	return s.String()
+}
+
+// fieldName returns node's Go field name. seen maps the field names used so
+// far in the enclosing struct to their paths.
+func (b *structBuilder) fieldName(node *YamlNode, seen map[string]string) string {
+	name := node.GoName
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		b.errs = append(b.errs, fmt.Errorf("%s: @go-name %q is not an exported Go identifier", node.Path, name))
+	} else if other, ok := seen[name]; ok {
+		b.errs = append(b.errs, fmt.Errorf("%s and %s both map to field %s; rename one with # @go-name", other, node.Path, name))
+	}
+	seen[name] = node.Path
+	return name
+}
+
+// claimType records that the section at path generates typeName, and
+// reports whether the name was still free.
+func (b *structBuilder) claimType(typeName, path string) bool {
+	owner, taken := b.owners[typeName]
+	switch {
+	case !taken:
+		b.owners[typeName] = path
+		return true
+	case owner == "":
+		b.errs = append(b.errs, fmt.Errorf("%s maps to type %s, which the config package already declares; rename it with # @go-name or # @type", path, typeName))
+	default:
+		b.errs = append(b.errs, fmt.Errorf("%s and %s both map to type %s; rename one with # @go-name or # @type", owner, path, typeName))
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"project/internal/environ"
+	"project/internal/placeholder"
+)
+
+// validateEnv checks the variables the template needs against env, the
+// layers and secrets directories the loader reads at runtime. It reports
+// required variables that are missing or empty, values that do not parse as
+// the variable's type or are not among its allowed values, and dotenv
+// variables nothing reads.
+func validateEnv(profile string, env environ.Env) []finding {
+	root := readTemplateTree()
+	docs := collectEnvVarDocs(root, templatePath)
+
+	leaves := make(map[string]string)
+	collectScalars(root, leaves)
+	if profile != "" {
+		if overlay := readOverlayTree(profile); overlay != nil {
+			docs = mergeEnvVarDocs(docs, collectEnvVarDocs(overlay, profileOverlayPath(profile)))
+			collectScalars(overlay, leaves)
+		}
+	}
+
+	lookup := func(name string) (string, bool) {
+		value, _, ok, _ := env.Resolve(name)
+		return value, ok
+	}
+
+	var findings []finding
+	known := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		known[doc.Name] = true
+
+		value, origin, ok, err := env.Resolve(doc.Name)
+		if err != nil {
+			findings = append(findings, finding{
+				Rule: ruleMissingEnv, Severity: severityError, Var: doc.Name, Path: doc.Path, File: doc.File, Line: doc.Line,
+				Message: err.Error(),
+			})
+			continue
+		}
+
+		if !ok || value == "" {
+			if doc.Required && conditionsHold(doc.RequiredIf, leaves, env.Layers, lookup) {
+				state := "not set"
+				if ok {
+					state = "empty"
+				}
+				findings = append(findings, finding{
+					Rule: ruleMissingEnv, Severity: severityError, Var: doc.Name, Path: doc.Path, File: doc.File, Line: doc.Line,
+					Message: fmt.Sprintf("%s is required by %s but %s", doc.Name, doc.Path, state),
+				})
+			}
+			continue
+		}
+
+		file, line := originLocation(origin, doc.Name)
+		if !parsesAs(doc.Type, value) {
+			findings = append(findings, finding{
+				Rule: ruleTypeMismatch, Severity: severityError, Var: doc.Name, Path: doc.Path, File: file, Line: line,
+				Message: fmt.Sprintf("%s=%s is not a valid %s", doc.Name, value, doc.Type),
+			})
+		} else if len(doc.Enum) > 0 && !slices.Contains(doc.Enum, value) {
+			findings = append(findings, finding{
+				Rule: ruleTypeMismatch, Severity: severityError, Var: doc.Name, Path: doc.Path, File: file, Line: line,
+				Message: fmt.Sprintf("%s=%s is not one of %s", doc.Name, value, strings.Join(doc.Enum, ", ")),
+			})
+		}
+	}
+
+	paths := make(map[string]bool)
+	for _, f := range collectFieldMeta(root) {
+		paths[f.Path] = true
+	}
+	for _, layer := range env.Layers {
+		if layer.Name == "process" {
+			continue
+		}
+		names := make([]string, 0, len(layer.Vars))
+		for name := range layer.Vars {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			if envVarUsed(name, known, paths) {
+				continue
+			}
+			findings = append(findings, finding{
+				Rule: ruleExtraEnv, Severity: severityWarning, Var: name, File: layer.Name, Line: dotenvLine(layer.Name, name),
+				Message: fmt.Sprintf("%s is set in %s but not used by the template", name, layer.Name),
+			})
+		}
+	}
+
+	return findings
+}
+
+// collectScalars records the raw value of every scalar leaf below node.
+func collectScalars(node *YamlNode, leaves map[string]string) {
+	for _, child := range node.Children {
+		collectScalars(child, leaves)
+	}
+	if len(node.Children) == 0 && node.Value != nil {
+		if _, ok := node.Value.([]interface{}); !ok {
+			leaves[node.Path] = fmt.Sprint(node.Value)
+		}
+	}
+}
+
+// conditionsHold reports whether every @required-if path is true in the
+// loaded configuration, the way the loader decides: the value is the
+// variable overriding the key if one is set in layers, or else the leaf
+// with its placeholders expanded, and only a value that parses as true
+// holds. A leaf that cannot be expanded has no value and does not hold.
+func conditionsHold(conditions []string, leaves map[string]string, layers environ.Stack, lookup placeholder.Lookup) bool {
+	for _, path := range conditions {
+		value, ok := overrideValue(path, layers)
+		if !ok {
+			t, err := placeholder.Parse(leaves[path])
+			if err != nil {
+				return false
+			}
+			if value, err = t.Expand(lookup, nil); err != nil {
+				return false
+			}
+		}
+		if enabled, err := strconv.ParseBool(value); err != nil || !enabled {
+			return false
+		}
+	}
+	return true
+}
+
+// overrideValue returns the value of the variable that overrides the key
+// path, such as WEB_PORT for web.port.
+func overrideValue(path string, layers environ.Stack) (string, bool) {
+	name := strings.ToUpper(strings.ReplaceAll(path, ".", "_"))
+	if strings.ReplaceAll(strings.ToLower(name), "_", ".") != path {
+		return "", false
+	}
+	return layers.Lookup(name)
+}
+
+func parsesAs(goType, value string) bool {
+	var err error
+	switch goType {
+	case "int":
+		_, err = strconv.Atoi(value)
+	case "float64":
+		_, err = strconv.ParseFloat(value, 64)
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	}
+	return err == nil
+}
+
+// originLocation returns the dotenv file and line a variable was read from.
+// Variables from the process environment or secret files have no line.
+func originLocation(origin environ.Origin, name string) (string, int) {
+	switch {
+	case origin.Via != "":
+		return origin.Layer, dotenvLine(origin.Layer, origin.Via)
+	case origin.File != "":
+		return origin.File, 0
+	case origin.Layer == "process":
+		return "", 0
+	default:
+		return origin.Layer, dotenvLine(origin.Layer, name)
+	}
+}
+
+// dotenvLine returns the line of filename that sets name, or 0.
+func dotenvLine(filename, name string) int {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return 0
+	}
+	for i, line := range strings.Split(string(content), "\n") {
+		if key, _, ok := environ.ParseLine(line); ok && key == name {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"project/internal/environ"
+)
+
+func TestConditionsHold(t *testing.T) {
+	leaves := map[string]string{
+		"auth.google.enabled":  "true",
+		"auth.discord.enabled": "${DISCORD_ENABLED:false}",
+		"auth.github.enabled":  "${GITHUB_ENABLED}",
+		"auth.gitlab.enabled":  "yes",
+		"auth.broken.enabled":  "${",
+	}
+
+	tests := []struct {
+		name       string
+		conditions []string
+		vars       map[string]string
+		want       bool
+	}{
+		{"no conditions", nil, nil, true},
+		{"literal true", []string{"auth.google.enabled"}, nil, true},
+		{"default false", []string{"auth.discord.enabled"}, nil, false},
+		{"placeholder set", []string{"auth.discord.enabled"}, map[string]string{"DISCORD_ENABLED": "true"}, true},
+		{"overridden key", []string{"auth.google.enabled"}, map[string]string{"AUTH_GOOGLE_ENABLED": "false"}, false},
+		{"required variable unset", []string{"auth.github.enabled"}, nil, false},
+		{"not a bool", []string{"auth.gitlab.enabled"}, nil, false},
+		{"unparsable leaf", []string{"auth.broken.enabled"}, nil, false},
+		{"missing leaf", []string{"auth.missing.enabled"}, nil, false},
+		{"all must hold", []string{"auth.google.enabled", "auth.discord.enabled"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layers := environ.Stack{{Name: "test", Vars: tt.vars}}
+			lookup := func(name string) (string, bool) {
+				return layers.Lookup(name)
+			}
+			if got := conditionsHold(tt.conditions, leaves, layers, lookup); got != tt.want {
+				t.Errorf("conditionsHold(%v) = %v, want %v", tt.conditions, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateEnvSecretsDirs(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	const template = `
+database:
+  password: "${DB_PASSWORD}"
+cache:
+  password: "${CACHE_PASSWORD}"
+`
+	if err := os.MkdirAll("config", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(templatePath, []byte(template), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var dirs []string
+	for _, name := range []string{"DB_PASSWORD", "CACHE_PASSWORD"} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("s3cret"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	env := environ.Env{Layers: environ.Stack{{Name: "test"}}, SecretsDirs: dirs}
+	for _, f := range validateEnv("", env) {
+		t.Errorf("unexpected finding: %s", f.Message)
+	}
+
+	env.SecretsDirs = dirs[:1]
+	findings := validateEnv("", env)
+	if len(findings) != 1 || findings[0].Var != "CACHE_PASSWORD" {
+		t.Errorf("findings = %+v, want CACHE_PASSWORD missing", findings)
+	}
+}